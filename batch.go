@@ -0,0 +1,238 @@
+package sandbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// FaultSpec describes a syscall to fail during a sandboxed run, for
+// crash-reproduction and fuzzing workflows driven through RunBatch's
+// FaultInjection option.
+//
+// Actually enforcing it requires a cooperating LD_PRELOAD shim (or, on
+// Linux, prctl(PR_SET_FAULT_INJECTION) under a fault-injection-capable
+// kernel with CAP_SYS_ADMIN) to be in place before the sandboxed
+// process execs - something RunBatch can't retrofit onto an
+// already-constructed Sandbox. FaultInjectionEnv exposes the markers
+// such a shim would read; callers that want a FaultSpec enforced must
+// append them to Config.Env themselves before constructing the Sandbox
+// passed to RunBatch.
+type FaultSpec struct {
+	// Syscall is the name of the syscall to fail, e.g. "open".
+	Syscall string
+
+	// Nth is which invocation (1-indexed) of Syscall to fail.
+	Nth int
+}
+
+// FaultInjectionEnv returns the environment variables a cooperating
+// LD_PRELOAD shim can read to fail spec.Syscall on its Nth call. See
+// FaultSpec for why RunBatch can't wire this in on its own.
+func FaultInjectionEnv(spec *FaultSpec) []string {
+	if spec == nil {
+		return nil
+	}
+	return []string{
+		"SANDBOX_FAULT_SYSCALL=" + spec.Syscall,
+		fmt.Sprintf("SANDBOX_FAULT_NTH=%d", spec.Nth),
+	}
+}
+
+// RunOptions configures a batch of invocations driven through RunBatch,
+// modeled on syzkaller's ipc/executor flags for crash reproduction and
+// fuzzing: running a reproducer repeatedly, in parallel, and recording
+// a trace of what happened each time.
+type RunOptions struct {
+	// Repeat re-invokes the command this many times, reusing the same
+	// Sandbox (and its cgroup/job handle) across invocations. Zero or
+	// negative is treated as 1.
+	Repeat int
+
+	// Parallel launches this many copies of each invocation
+	// concurrently, sharing the Sandbox's limits. Zero or negative is
+	// treated as 1.
+	Parallel int
+
+	// Collide starts all of a round's Parallel copies as close to
+	// simultaneously as possible, instead of however goroutine
+	// scheduling happens to stagger them, to surface races a reproducer
+	// relies on overlapping timing to trigger.
+	Collide bool
+
+	// Threaded runs a round's Parallel copies as concurrent goroutines
+	// rather than one after another. Implied whenever Parallel > 1.
+	Threaded bool
+
+	// FaultInjection, if set, names a syscall to fail on its Nth call.
+	// See FaultSpec for how to wire it into the Sandbox being run.
+	FaultInjection *FaultSpec
+
+	// RecordTrace, if set, receives one JSON line per invocation as it
+	// completes, in the shape {"exit","duration_ms","peak_rss",
+	// "timed_out","oom"} - enough for a caller driving this module as a
+	// fuzzer's executor to log a reproducer's behavior across runs.
+	RecordTrace io.Writer
+}
+
+// BatchResult aggregates the Results from a RunBatch call.
+type BatchResult struct {
+	// Results holds every invocation's Result, grouped by round in the
+	// order RunOptions.Repeat ran them; within a round, order matches
+	// launch order regardless of which invocation finished first.
+	Results []*Result
+
+	// CrashCount is how many invocations exited abnormally: a nonzero
+	// exit code, a timeout, or a resource/seccomp kill.
+	CrashCount int
+
+	// MinExecutionTime/MaxExecutionTime/MeanExecutionTime summarize
+	// Results[*].ExecutionTime.
+	MinExecutionTime  time.Duration
+	MaxExecutionTime  time.Duration
+	MeanExecutionTime time.Duration
+}
+
+// traceEntry is the JSON shape RunBatch writes to RunOptions.RecordTrace,
+// one line per invocation.
+type traceEntry struct {
+	Exit       int   `json:"exit"`
+	DurationMS int64 `json:"duration_ms"`
+	PeakRSS    int64 `json:"peak_rss"`
+	TimedOut   bool  `json:"timed_out"`
+	OOM        bool  `json:"oom"`
+}
+
+// isCrash reports whether r represents an abnormal invocation: a
+// nonzero exit, a timeout, or a resource/seccomp kill.
+func isCrash(r *Result) bool {
+	return r.ExitCode != 0 ||
+		r.TimedOut ||
+		r.MemoryExceeded ||
+		r.CPUTimeExceeded ||
+		r.FileSizeExceeded ||
+		r.RealTimeExceeded ||
+		r.KilledBySeccomp
+}
+
+// RunBatch drives sb through opts.Repeat rounds of opts.Parallel
+// concurrent invocations of command, the way a fuzzer's executor
+// replays a reproducer to confirm or characterize a crash. A Run error
+// (as opposed to a nonzero exit, timeout, or resource kill, which are
+// all just reflected in that invocation's Result) aborts the batch
+// immediately, returning whatever Results were collected so far.
+func RunBatch(ctx context.Context, sb Sandbox, command string, opts RunOptions, args ...string) (*BatchResult, error) {
+	repeat := opts.Repeat
+	if repeat <= 0 {
+		repeat = 1
+	}
+	parallel := opts.Parallel
+	if parallel <= 0 {
+		parallel = 1
+	}
+
+	batch := &BatchResult{}
+	var traceMu sync.Mutex
+
+	for round := 0; round < repeat; round++ {
+		results := make([]*Result, parallel)
+		errs := make([]error, parallel)
+
+		var start chan struct{}
+		if opts.Collide {
+			start = make(chan struct{})
+		}
+
+		invoke := func(i int) {
+			if start != nil {
+				<-start
+			}
+			results[i], errs[i] = sb.Run(ctx, command, args...)
+		}
+
+		if parallel > 1 || opts.Threaded {
+			var wg sync.WaitGroup
+			for i := 0; i < parallel; i++ {
+				i := i
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					invoke(i)
+				}()
+			}
+			if start != nil {
+				close(start)
+			}
+			wg.Wait()
+		} else {
+			if start != nil {
+				close(start)
+			}
+			invoke(0)
+		}
+
+		for i := 0; i < parallel; i++ {
+			if errs[i] != nil {
+				return batch, errs[i]
+			}
+			result := results[i]
+			batch.Results = append(batch.Results, result)
+			if isCrash(result) {
+				batch.CrashCount++
+			}
+			if opts.RecordTrace != nil {
+				if err := writeTrace(opts.RecordTrace, &traceMu, result); err != nil {
+					return batch, err
+				}
+			}
+		}
+	}
+
+	batch.summarize()
+	return batch, nil
+}
+
+// writeTrace appends one JSON line describing r to w, serializing
+// concurrent invocations' writes with mu so lines from a Parallel round
+// don't interleave.
+func writeTrace(w io.Writer, mu *sync.Mutex, r *Result) error {
+	line, err := json.Marshal(traceEntry{
+		Exit:       r.ExitCode,
+		DurationMS: r.ExecutionTime.Milliseconds(),
+		PeakRSS:    r.MaxRSSBytes,
+		TimedOut:   r.TimedOut,
+		OOM:        r.MemoryExceeded,
+	})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	mu.Lock()
+	defer mu.Unlock()
+	_, err = w.Write(line)
+	return err
+}
+
+// summarize fills in Min/Max/MeanExecutionTime from b.Results.
+func (b *BatchResult) summarize() {
+	if len(b.Results) == 0 {
+		return
+	}
+	var total time.Duration
+	b.MinExecutionTime = b.Results[0].ExecutionTime
+	for _, r := range b.Results {
+		d := r.ExecutionTime
+		total += d
+		if d < b.MinExecutionTime {
+			b.MinExecutionTime = d
+		}
+		if d > b.MaxExecutionTime {
+			b.MaxExecutionTime = d
+		}
+	}
+	b.MeanExecutionTime = total / time.Duration(len(b.Results))
+}
@@ -0,0 +1,124 @@
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunBatchRepeat(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	config := &Config{
+		Timeout:     5 * time.Second,
+		MemoryLimit: 100 * 1024 * 1024,
+	}
+	sb, err := New(config)
+	if err != nil {
+		t.Fatalf("Failed to create sandbox: %v", err)
+	}
+	defer sb.Cleanup()
+
+	batch, err := RunBatch(context.Background(), sb, "sh", RunOptions{Repeat: 3}, "-c", "exit 0")
+	if err != nil {
+		t.Fatalf("RunBatch failed: %v", err)
+	}
+
+	if len(batch.Results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(batch.Results))
+	}
+	if batch.CrashCount != 0 {
+		t.Errorf("Expected 0 crashes, got %d", batch.CrashCount)
+	}
+}
+
+func TestRunBatchParallel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	config := &Config{
+		Timeout:     5 * time.Second,
+		MemoryLimit: 100 * 1024 * 1024,
+	}
+	sb, err := New(config)
+	if err != nil {
+		t.Fatalf("Failed to create sandbox: %v", err)
+	}
+	defer sb.Cleanup()
+
+	batch, err := RunBatch(context.Background(), sb, "sh", RunOptions{Parallel: 4}, "-c", "exit 0")
+	if err != nil {
+		t.Fatalf("RunBatch failed: %v", err)
+	}
+
+	if len(batch.Results) != 4 {
+		t.Fatalf("Expected 4 results, got %d", len(batch.Results))
+	}
+}
+
+func TestRunBatchCrashCount(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	config := &Config{
+		Timeout:     5 * time.Second,
+		MemoryLimit: 100 * 1024 * 1024,
+	}
+	sb, err := New(config)
+	if err != nil {
+		t.Fatalf("Failed to create sandbox: %v", err)
+	}
+	defer sb.Cleanup()
+
+	batch, err := RunBatch(context.Background(), sb, "sh", RunOptions{Repeat: 2}, "-c", "exit 1")
+	if err != nil {
+		t.Fatalf("RunBatch failed: %v", err)
+	}
+
+	if batch.CrashCount != 2 {
+		t.Errorf("Expected 2 crashes, got %d", batch.CrashCount)
+	}
+}
+
+func TestRunBatchRecordTrace(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	config := &Config{
+		Timeout:     5 * time.Second,
+		MemoryLimit: 100 * 1024 * 1024,
+	}
+	sb, err := New(config)
+	if err != nil {
+		t.Fatalf("Failed to create sandbox: %v", err)
+	}
+	defer sb.Cleanup()
+
+	var trace bytes.Buffer
+	opts := RunOptions{Repeat: 2, RecordTrace: &trace}
+
+	if _, err := RunBatch(context.Background(), sb, "sh", opts, "-c", "exit 0"); err != nil {
+		t.Fatalf("RunBatch failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(trace.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 trace lines, got %d", len(lines))
+	}
+	var entry traceEntry
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("Failed to decode trace line: %v", err)
+	}
+	if entry.Exit != 0 {
+		t.Errorf("Expected exit 0 in trace, got %d", entry.Exit)
+	}
+}
@@ -0,0 +1,154 @@
+// +build linux
+
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+func init() {
+	Register("bwrap", func(config *Config) (Sandbox, error) {
+		return NewBubblewrapSandbox(config)
+	}, DriverCapabilities{
+		NamespaceIsolation: true,
+		NetworkIsolation:   true,
+	})
+}
+
+// BubblewrapSandbox runs commands under bubblewrap (bwrap), translating
+// this package's Config into the --bind/--ro-bind/--unshare-* flags
+// bwrap expects. It's the lightweight alternative to the gvisor driver:
+// namespace isolation without a user-space kernel, at the cost of
+// sharing the host kernel's syscall surface.
+type BubblewrapSandbox struct {
+	config   *Config
+	bwrapBin string
+}
+
+// NewBubblewrapSandbox creates a sandbox backed by bwrap.
+func NewBubblewrapSandbox(config *Config) (*BubblewrapSandbox, error) {
+	if config == nil {
+		config = &Config{}
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+
+	bwrapBin, err := exec.LookPath("bwrap")
+	if err != nil {
+		return nil, fmt.Errorf("bwrap driver: bwrap not found on PATH: %w", err)
+	}
+
+	return &BubblewrapSandbox{config: config, bwrapBin: bwrapBin}, nil
+}
+
+// bwrapArgs translates Config into bubblewrap command-line flags.
+func (s *BubblewrapSandbox) bwrapArgs(command string, args []string) []string {
+	flags := []string{"--die-with-parent", "--ro-bind", "/", "/"}
+	if s.config.RootFS != "" {
+		flags = []string{"--die-with-parent", "--bind", s.config.RootFS, "/", "--proc", "/proc"}
+	}
+
+	for _, dir := range s.config.AllowedDirs {
+		flags = append(flags, "--bind", dir, dir)
+	}
+	for _, dir := range s.config.ReadOnlyDirs {
+		flags = append(flags, "--ro-bind", dir, dir)
+	}
+
+	if s.config.Namespaces&NSNet != 0 || !s.config.NetworkAccess {
+		flags = append(flags, "--unshare-net")
+	}
+	if s.config.Namespaces&NSUser != 0 {
+		flags = append(flags, "--unshare-user")
+	}
+	if s.config.Namespaces&NSPid != 0 {
+		flags = append(flags, "--unshare-pid")
+	}
+	if s.config.Namespaces&NSIPC != 0 {
+		flags = append(flags, "--unshare-ipc")
+	}
+	if s.config.Namespaces&NSUTS != 0 {
+		flags = append(flags, "--unshare-uts")
+		if s.config.Hostname != "" {
+			flags = append(flags, "--hostname", s.config.Hostname)
+		}
+	}
+	if s.config.WorkingDir != "" {
+		flags = append(flags, "--chdir", s.config.WorkingDir)
+	}
+
+	flags = append(flags, "--")
+	flags = append(flags, command)
+	flags = append(flags, args...)
+	return flags
+}
+
+// Run executes a command in the sandbox.
+func (s *BubblewrapSandbox) Run(ctx context.Context, command string, args ...string) (*Result, error) {
+	result := &Result{}
+	startTime := time.Now()
+
+	cmdCtx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(cmdCtx, s.bwrapBin, s.bwrapArgs(command, args)...)
+	if len(s.config.Env) > 0 {
+		cmd.Env = s.config.Env
+	} else {
+		cmd.Env = os.Environ()
+	}
+	cmd.Stdin = s.config.Stdin
+	cmd.Stdout = s.config.Stdout
+	cmd.Stderr = s.config.Stderr
+
+	err := cmd.Run()
+	result.ExecutionTime = time.Since(startTime)
+
+	if cmdCtx.Err() == context.DeadlineExceeded {
+		result.TimedOut = true
+		result.Error = fmt.Errorf("execution timeout exceeded")
+	}
+
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		result.ExitCode = exitErr.ExitCode()
+	} else if err == nil {
+		result.ExitCode = 0
+	} else if result.Error == nil {
+		result.Error = fmt.Errorf("bwrap: %w", err)
+	}
+
+	return result, nil
+}
+
+// Start launches command in the sandbox without waiting for it to exit.
+func (s *BubblewrapSandbox) Start(ctx context.Context, command string, args ...string) (Process, error) {
+	cmd := exec.CommandContext(ctx, s.bwrapBin, s.bwrapArgs(command, args)...)
+	if len(s.config.Env) > 0 {
+		cmd.Env = s.config.Env
+	} else {
+		cmd.Env = os.Environ()
+	}
+	cmd.Stdin = s.config.Stdin
+	cmd.Stdout = s.config.Stdout
+	cmd.Stderr = s.config.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting bwrap: %w", err)
+	}
+
+	return &execProcess{
+		cmd:       cmd,
+		startTime: time.Now(),
+		startFn:   func(ctx context.Context, c string, a ...string) (Process, error) { return s.Start(ctx, c, a...) },
+	}, nil
+}
+
+// Cleanup releases resources used by the sandbox.
+func (s *BubblewrapSandbox) Cleanup() error {
+	return nil
+}
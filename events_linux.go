@@ -0,0 +1,335 @@
+// +build linux
+
+package sandbox
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const defaultSampleInterval = 500 * time.Millisecond
+
+// eventSampler polls a sandbox's cgroup for resource usage at
+// Config.SampleInterval and watches memory.events via inotify so OOM
+// kills are reported promptly rather than only after the fact in
+// Result.MemoryExceeded.
+type eventSampler struct {
+	sandbox *LinuxSandbox
+	events  chan<- Event
+
+	stop chan struct{}
+	done chan struct{}
+
+	mu          sync.Mutex
+	seenPids    map[int]bool
+	peakRSS     int64
+	cpuTime     time.Duration
+	ioRead      int64
+	ioWrite     int64
+	lastCPUUsec int64
+	thresholded bool
+}
+
+// newEventSampler starts polling and OOM-watching goroutines for pid, or
+// returns nil if the sandbox has no Events channel configured.
+func newEventSampler(s *LinuxSandbox, pid int) *eventSampler {
+	if s.config.Events == nil {
+		return nil
+	}
+
+	es := &eventSampler{
+		sandbox:  s,
+		events:   s.config.Events,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		seenPids: map[int]bool{pid: true},
+	}
+
+	es.emit(Event{Type: EventProcessStarted, PID: pid})
+
+	go es.sampleLoop()
+	go es.watchOOM()
+
+	return es
+}
+
+// emit sends an event, dropping it instead of blocking forever if the
+// caller's channel is unbuffered and not being read - the sampler must
+// never wedge the process it's observing.
+func (es *eventSampler) emit(evt Event) {
+	select {
+	case es.events <- evt:
+	case <-time.After(time.Second):
+	}
+}
+
+func (es *eventSampler) sampleLoop() {
+	interval := es.sandbox.config.SampleInterval
+	if interval <= 0 {
+		interval = defaultSampleInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-es.stop:
+			close(es.done)
+			return
+		case <-ticker.C:
+			es.sampleOnce(interval)
+		}
+	}
+}
+
+func (es *eventSampler) sampleOnce(interval time.Duration) {
+	cgroupPath := es.sandbox.cgroupPath
+	if cgroupPath == "" || !es.sandbox.useCgroupV2 {
+		return
+	}
+
+	rss := readInt64File(filepath.Join(cgroupPath, "memory.current"))
+	usec := readCPUUsageUsec(cgroupPath)
+	ioRead, ioWrite := readIOStat(cgroupPath)
+	pids := readCgroupProcs(cgroupPath)
+
+	es.mu.Lock()
+	if rss > es.peakRSS {
+		es.peakRSS = rss
+	}
+	cpuPct := 0.0
+	if es.lastCPUUsec > 0 && usec > es.lastCPUUsec {
+		cpuPct = float64(usec-es.lastCPUUsec) / float64(interval.Microseconds()) * 100
+	}
+	es.lastCPUUsec = usec
+	es.cpuTime = time.Duration(usec) * time.Microsecond
+	es.ioRead, es.ioWrite = ioRead, ioWrite
+
+	for _, pid := range pids {
+		if es.seenPids[pid] {
+			continue
+		}
+		es.seenPids[pid] = true
+		if ppid := readPPID(pid); ppid != 0 && es.seenPids[ppid] {
+			es.emit(Event{Type: EventChildForked, PID: pid, PPID: ppid})
+		}
+	}
+
+	limit := es.sandbox.config.MemoryLimit
+	thresholdCrossed := !es.thresholded && limit > 0 && rss > limit*9/10
+	if thresholdCrossed {
+		es.thresholded = true
+	}
+	es.mu.Unlock()
+
+	es.emit(Event{
+		Type: EventResourceSample,
+		Sample: &ResourceSample{
+			CPUPct:       cpuPct,
+			RSS:          rss,
+			IOReadBytes:  ioRead,
+			IOWriteBytes: ioWrite,
+			NumPIDs:      len(pids),
+		},
+	})
+
+	if thresholdCrossed {
+		es.emit(Event{Type: EventCgroupThresholdExceeded})
+	}
+}
+
+// watchOOM blocks on inotify watching the cgroup's memory.events file
+// and emits EventOOMKill whenever the oom_kill counter increases.
+func (es *eventSampler) watchOOM() {
+	cgroupPath := es.sandbox.cgroupPath
+	if cgroupPath == "" || !es.sandbox.useCgroupV2 {
+		return
+	}
+	eventsPath := filepath.Join(cgroupPath, "memory.events")
+
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC)
+	if err != nil {
+		return
+	}
+	defer syscall.Close(fd)
+
+	wd, err := syscall.InotifyAddWatch(fd, eventsPath, syscall.IN_MODIFY)
+	if err != nil {
+		return
+	}
+	defer syscall.InotifyRmWatch(fd, uint32(wd))
+
+	lastOOM := readOOMKillCount(eventsPath)
+	buf := make([]byte, syscall.SizeofInotifyEvent*8)
+
+	for {
+		select {
+		case <-es.stop:
+			return
+		default:
+		}
+
+		// Bound the blocking read so <-es.stop is still checked
+		// periodically after the filesystem goes quiet.
+		if !waitReadable(fd, time.Second) {
+			continue
+		}
+
+		n, err := syscall.Read(fd, buf)
+		if err != nil || n == 0 {
+			continue
+		}
+
+		current := readOOMKillCount(eventsPath)
+		if current > lastOOM {
+			pid := 0
+			if pids := readCgroupProcs(cgroupPath); len(pids) > 0 {
+				pid = pids[0]
+			}
+			es.emit(Event{Type: EventOOMKill, PID: pid})
+			lastOOM = current
+		}
+	}
+}
+
+// waitReadable polls fd with select(2) for up to timeout, returning
+// whether it became readable. select is used instead of a blocking read
+// so watchOOM can still observe es.stop.
+func waitReadable(fd int, timeout time.Duration) bool {
+	var rfds syscall.FdSet
+	fdIdx := fd / 64
+	rfds.Bits[fdIdx] = 1 << uint(fd%64)
+
+	tv := syscall.NsecToTimeval(timeout.Nanoseconds())
+	n, err := syscall.Select(fd+1, &rfds, nil, nil, &tv)
+	return err == nil && n > 0
+}
+
+// Stop stops the sampler's polling goroutine and emits EventProcessExited
+// for pid. The aggregated peak RSS / CPU time / I/O stats are copied into
+// a Result separately, by populateResult.
+func (es *eventSampler) Stop(pid int) {
+	close(es.stop)
+	<-es.done
+	es.emit(Event{Type: EventProcessExited, PID: pid})
+}
+
+func (es *eventSampler) populateResult(result *Result) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	result.PeakRSS = es.peakRSS
+	result.CPUTime = es.cpuTime
+	result.IOStats = IOStats{ReadBytes: es.ioRead, WriteBytes: es.ioWrite}
+}
+
+func readInt64File(path string) int64 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	val, _ := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	return val
+}
+
+func readCPUUsageUsec(cgroupPath string) int64 {
+	data, err := os.ReadFile(filepath.Join(cgroupPath, "cpu.stat"))
+	if err != nil {
+		return 0
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "usage_usec" {
+			val, _ := strconv.ParseInt(fields[1], 10, 64)
+			return val
+		}
+	}
+	return 0
+}
+
+func readIOStat(cgroupPath string) (readBytes, writeBytes int64) {
+	data, err := os.ReadFile(filepath.Join(cgroupPath, "io.stat"))
+	if err != nil {
+		return 0, 0
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		for _, kv := range fields[1:] {
+			parts := strings.SplitN(kv, "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			val, err := strconv.ParseInt(parts[1], 10, 64)
+			if err != nil {
+				continue
+			}
+			switch parts[0] {
+			case "rbytes":
+				readBytes += val
+			case "wbytes":
+				writeBytes += val
+			}
+		}
+	}
+	return readBytes, writeBytes
+}
+
+func readCgroupProcs(cgroupPath string) []int {
+	data, err := os.ReadFile(filepath.Join(cgroupPath, "cgroup.procs"))
+	if err != nil {
+		return nil
+	}
+	var pids []int
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+		if pid, err := strconv.Atoi(line); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
+
+func readOOMKillCount(eventsPath string) int64 {
+	data, err := os.ReadFile(eventsPath)
+	if err != nil {
+		return 0
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "oom_kill" {
+			val, _ := strconv.ParseInt(fields[1], 10, 64)
+			return val
+		}
+	}
+	return 0
+}
+
+func readPPID(pid int) int {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0
+	}
+	// Fields after the ")" that closes the process name are
+	// space-separated; PPID is the second of those, after the state.
+	idx := strings.LastIndex(string(data), ")")
+	if idx == -1 {
+		return 0
+	}
+	fields := strings.Fields(string(data)[idx+1:])
+	if len(fields) < 2 {
+		return 0
+	}
+	ppid, _ := strconv.Atoi(fields[1])
+	return ppid
+}
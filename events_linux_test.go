@@ -0,0 +1,78 @@
+// +build linux
+
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, dir, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+	return path
+}
+
+func TestReadInt64File(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "memory.current", "12345\n")
+
+	if got := readInt64File(filepath.Join(dir, "memory.current")); got != 12345 {
+		t.Errorf("Expected 12345, got %d", got)
+	}
+	if got := readInt64File(filepath.Join(dir, "missing")); got != 0 {
+		t.Errorf("Expected 0 for missing file, got %d", got)
+	}
+}
+
+func TestReadCPUUsageUsec(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "cpu.stat", "usage_usec 98765\nuser_usec 50000\nsystem_usec 48765\n")
+
+	if got := readCPUUsageUsec(dir); got != 98765 {
+		t.Errorf("Expected 98765, got %d", got)
+	}
+}
+
+func TestReadIOStat(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "io.stat", "8:0 rbytes=1024 wbytes=2048 rios=1 wios=1\n8:16 rbytes=512 wbytes=256 rios=1 wios=1\n")
+
+	readBytes, writeBytes := readIOStat(dir)
+	if readBytes != 1536 {
+		t.Errorf("Expected readBytes 1536, got %d", readBytes)
+	}
+	if writeBytes != 2304 {
+		t.Errorf("Expected writeBytes 2304, got %d", writeBytes)
+	}
+}
+
+func TestReadCgroupProcs(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "cgroup.procs", "123\n456\n789\n")
+
+	pids := readCgroupProcs(dir)
+	if len(pids) != 3 || pids[0] != 123 || pids[1] != 456 || pids[2] != 789 {
+		t.Errorf("Expected [123 456 789], got %v", pids)
+	}
+}
+
+func TestReadOOMKillCount(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestFile(t, dir, "memory.events", "low 0\nhigh 0\nmax 0\noom 0\noom_kill 2\n")
+
+	if got := readOOMKillCount(path); got != 2 {
+		t.Errorf("Expected 2, got %d", got)
+	}
+}
+
+func TestReadPPID(t *testing.T) {
+	ppid := readPPID(os.Getpid())
+	if ppid != os.Getppid() {
+		t.Errorf("Expected PPID %d, got %d", os.Getppid(), ppid)
+	}
+}
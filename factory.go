@@ -1,7 +1,28 @@
 package sandbox
 
-// New creates a new sandbox instance for the current OS
-// The implementation is platform-specific and defined in factory_*.go files
+const driverNative = "native"
+
+func init() {
+	// Seccomp and namespace isolation are only enforced by the native
+	// driver on Linux; on other platforms the corresponding Config
+	// fields are accepted but ignored.
+	Register(driverNative, newPlatformSandbox, DriverCapabilities{
+		MemoryLimit:        true,
+		CPULimit:           true,
+		Seccomp:            true,
+		NamespaceIsolation: true,
+	})
+}
+
+// New creates a new sandbox instance for the current OS, using the
+// driver named in config.Driver (or the built-in "native" cgroup/
+// job-object backend if unset).
+// The native implementation is platform-specific and defined in
+// factory_*.go files.
 func New(config *Config) (Sandbox, error) {
-	return newPlatformSandbox(config)
+	driver := driverNative
+	if config != nil && config.Driver != "" {
+		driver = config.Driver
+	}
+	return NewWithDriver(driver, config)
 }
@@ -0,0 +1,233 @@
+// +build linux
+
+package sandbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+)
+
+// cloneFlag maps a NamespaceFlags bit to the corresponding CLONE_NEW*
+// flag consumed by SysProcAttr.Cloneflags.
+func cloneFlag(ns NamespaceFlags) uintptr {
+	var flags uintptr
+	if ns&NSPid != 0 {
+		flags |= syscall.CLONE_NEWPID
+	}
+	if ns&NSNet != 0 {
+		flags |= syscall.CLONE_NEWNET
+	}
+	if ns&NSMount != 0 {
+		flags |= syscall.CLONE_NEWNS
+	}
+	if ns&NSUTS != 0 {
+		flags |= syscall.CLONE_NEWUTS
+	}
+	if ns&NSIPC != 0 {
+		flags |= syscall.CLONE_NEWIPC
+	}
+	if ns&NSUser != 0 {
+		flags |= syscall.CLONE_NEWUSER
+	}
+	return flags
+}
+
+// applyNamespaces configures cmd.SysProcAttr to isolate the child into
+// the namespaces requested by s.config.Namespaces. When the caller isn't
+// root, it automatically folds in a user namespace (with a
+// current-uid-to-root mapping) so the other namespace creations don't
+// require privileges this process doesn't have - the same fallback
+// rootless gVisor and buildah use.
+func (s *LinuxSandbox) applyNamespaces(cmd *exec.Cmd) {
+	ns := s.config.Namespaces
+	if ns == 0 && s.config.RootFS == "" {
+		return
+	}
+	if ns != 0 && os.Geteuid() != 0 {
+		ns |= NSUser
+	}
+
+	cmd.SysProcAttr.Cloneflags |= cloneFlag(ns)
+
+	if ns&NSUser == 0 {
+		return
+	}
+
+	uidMap := s.config.UIDMap
+	if len(uidMap) == 0 {
+		uidMap = []IDMap{{ContainerID: 0, HostID: os.Geteuid(), Size: 1}}
+	}
+	gidMap := s.config.GIDMap
+	if len(gidMap) == 0 {
+		gidMap = []IDMap{{ContainerID: 0, HostID: os.Getegid(), Size: 1}}
+	}
+
+	cmd.SysProcAttr.UidMappings = toSyscallIDMap(uidMap)
+	cmd.SysProcAttr.GidMappings = toSyscallIDMap(gidMap)
+	// A single-entry, self-authored mapping doesn't need the
+	// newuidmap/newgidmap setuid helpers.
+	cmd.SysProcAttr.GidMappingsEnableSetgroups = false
+}
+
+func toSyscallIDMap(in []IDMap) []syscall.SysProcIDMap {
+	out := make([]syscall.SysProcIDMap, len(in))
+	for i, m := range in {
+		out[i] = syscall.SysProcIDMap{
+			ContainerID: m.ContainerID,
+			HostID:      m.HostID,
+			Size:        m.Size,
+		}
+	}
+	return out
+}
+
+// rootfsTrampolineEnv carries the JSON-encoded rootfsSetup for the
+// re-exec trampoline (see seccomp_linux.go's init) to apply before
+// handing off to the seccomp filter and, ultimately, the target exec.
+const rootfsTrampolineEnv = "GO_PROC_SANDBOX_ROOTFS_SETUP"
+
+// rootfsSetup is the information the trampoline needs to turn RootFS
+// into the child's new root filesystem before exec.
+type rootfsSetup struct {
+	RootFS       string
+	Mounts       []MountSpec
+	Hostname     string
+	AllowedDirs  []string
+	ReadOnlyDirs []string
+}
+
+// encodeRootfsSetup serializes the sandbox's filesystem-isolation config
+// for the trampoline, or returns ("", nil) if RootFS isn't set.
+func (s *LinuxSandbox) encodeRootfsSetup() (string, error) {
+	if s.config.RootFS == "" {
+		return "", nil
+	}
+	setup := rootfsSetup{
+		RootFS:       s.config.RootFS,
+		Mounts:       s.config.Mounts,
+		Hostname:     s.config.Hostname,
+		AllowedDirs:  s.config.AllowedDirs,
+		ReadOnlyDirs: s.config.ReadOnlyDirs,
+	}
+	encoded, err := json.Marshal(setup)
+	if err != nil {
+		return "", fmt.Errorf("encoding rootfs setup: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// applyRootfsSetup runs inside the re-exec trampoline, after fork but
+// before the target is exec'd. It mounts AllowedDirs/ReadOnlyDirs as
+// bind mounts under RootFS (turning those config fields from
+// documentation into enforcement), mounts /proc, sets the hostname, and
+// pivots into RootFS as the new root.
+func applyRootfsSetup(setup rootfsSetup) error {
+	if setup.Hostname != "" {
+		if err := syscall.Sethostname([]byte(setup.Hostname)); err != nil {
+			return fmt.Errorf("sethostname: %w", err)
+		}
+	}
+
+	// Mounts propagate to the host's view of this mount namespace unless
+	// we make our copy private first.
+	if err := syscall.Mount("", "/", "", syscall.MS_PRIVATE|syscall.MS_REC, ""); err != nil {
+		return fmt.Errorf("making mount namespace private: %w", err)
+	}
+
+	for _, dir := range setup.AllowedDirs {
+		if err := bindMount(dir, filepath.Join(setup.RootFS, dir), false); err != nil {
+			return err
+		}
+	}
+	for _, dir := range setup.ReadOnlyDirs {
+		if err := bindMount(dir, filepath.Join(setup.RootFS, dir), true); err != nil {
+			return err
+		}
+	}
+	for _, m := range setup.Mounts {
+		target := filepath.Join(setup.RootFS, m.Target)
+		if m.FSType == "" {
+			if err := bindMount(m.Source, target, m.ReadOnly); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.MkdirAll(target, 0755); err != nil {
+			return fmt.Errorf("mkdir %s: %w", target, err)
+		}
+		if err := syscall.Mount(m.Source, target, m.FSType, 0, ""); err != nil {
+			return fmt.Errorf("mount %s on %s: %w", m.FSType, target, err)
+		}
+	}
+
+	procTarget := filepath.Join(setup.RootFS, "proc")
+	if err := os.MkdirAll(procTarget, 0755); err != nil {
+		return fmt.Errorf("mkdir %s: %w", procTarget, err)
+	}
+	if err := syscall.Mount("proc", procTarget, "proc", 0, ""); err != nil {
+		return fmt.Errorf("mount proc: %w", err)
+	}
+
+	return pivotRoot(setup.RootFS)
+}
+
+// bindMount bind-mounts src onto dst, creating dst and optionally
+// remounting it read-only.
+func bindMount(src, dst string, readOnly bool) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", src, err)
+	}
+	if info.IsDir() {
+		if err := os.MkdirAll(dst, 0755); err != nil {
+			return fmt.Errorf("mkdir %s: %w", dst, err)
+		}
+	} else {
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return fmt.Errorf("mkdir %s: %w", filepath.Dir(dst), err)
+		}
+		if f, err := os.OpenFile(dst, os.O_CREATE, 0644); err == nil {
+			f.Close()
+		}
+	}
+
+	if err := syscall.Mount(src, dst, "", syscall.MS_BIND, ""); err != nil {
+		return fmt.Errorf("bind mount %s on %s: %w", src, dst, err)
+	}
+	if readOnly {
+		if err := syscall.Mount(src, dst, "", syscall.MS_BIND|syscall.MS_REMOUNT|syscall.MS_RDONLY, ""); err != nil {
+			return fmt.Errorf("remount %s read-only: %w", dst, err)
+		}
+	}
+	return nil
+}
+
+// pivotRoot makes newRoot the process's root filesystem via
+// pivot_root(2), then unmounts the old root out of the way.
+func pivotRoot(newRoot string) error {
+	// pivot_root requires newRoot to be a mount point.
+	if err := syscall.Mount(newRoot, newRoot, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
+		return fmt.Errorf("bind mount rootfs onto itself: %w", err)
+	}
+
+	oldRoot := filepath.Join(newRoot, ".old_root")
+	if err := os.MkdirAll(oldRoot, 0700); err != nil {
+		return fmt.Errorf("mkdir old root: %w", err)
+	}
+	if err := syscall.PivotRoot(newRoot, oldRoot); err != nil {
+		return fmt.Errorf("pivot_root: %w", err)
+	}
+	if err := os.Chdir("/"); err != nil {
+		return fmt.Errorf("chdir to new root: %w", err)
+	}
+
+	oldRoot = "/.old_root"
+	if err := syscall.Unmount(oldRoot, syscall.MNT_DETACH); err != nil {
+		return fmt.Errorf("unmount old root: %w", err)
+	}
+	return os.RemoveAll(oldRoot)
+}
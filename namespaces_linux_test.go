@@ -0,0 +1,52 @@
+// +build linux
+
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRootFSPivotWithAllowedDirs verifies that setting Config.RootFS
+// actually pivots the child into that directory as its new root, with
+// AllowedDirs bind-mounted in so the command's interpreter is still
+// reachable - rather than just being accepted and ignored.
+func TestRootFSPivotWithAllowedDirs(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("pivot_root requires root or a working user-namespace fallback")
+	}
+
+	rootfs := t.TempDir()
+	marker := "go-proc-sandbox-rootfs-marker"
+	if err := os.WriteFile(filepath.Join(rootfs, marker), []byte("present"), 0644); err != nil {
+		t.Fatalf("Failed to write marker file: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	config := &Config{
+		Timeout:     5 * time.Second,
+		MemoryLimit: 100 * 1024 * 1024,
+		Namespaces:  NSMount,
+		RootFS:      rootfs,
+		AllowedDirs: []string{"/bin", "/lib", "/lib64", "/usr"},
+		Stdout:      &stdout,
+	}
+	sb, err := New(config)
+	if err != nil {
+		t.Fatalf("Failed to create sandbox: %v", err)
+	}
+	defer sb.Cleanup()
+
+	result, err := sb.Run(context.Background(), "sh", "-c", "test -f /"+marker+" && echo found || echo missing")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if got := strings.TrimSpace(stdout.String()); got != "found" {
+		t.Errorf("Expected the rootfs marker to be visible at / after pivot, got output %q (exit %d)", got, result.ExitCode)
+	}
+}
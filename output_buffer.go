@@ -0,0 +1,86 @@
+package sandbox
+
+import "sync"
+
+// defaultMaxOutputBytes is used when Config.MaxOutputBytes is unset.
+const defaultMaxOutputBytes = 1 << 20 // 1 MB
+
+// outputBuffer is an io.Writer that keeps only the most recent limit
+// bytes written to it, so capturing a sandboxed process's stdout/stderr
+// can't OOM the parent even if the child produces unbounded output. It
+// plays the same role as Nomad's executor's circbuf-backed log buffers.
+type outputBuffer struct {
+	mu      sync.Mutex
+	buf     []byte
+	limit   int64
+	written int64
+}
+
+// newOutputBuffer creates an outputBuffer capped at limit bytes.
+// limit <= 0 uses defaultMaxOutputBytes.
+func newOutputBuffer(limit int64) *outputBuffer {
+	if limit <= 0 {
+		limit = defaultMaxOutputBytes
+	}
+	return &outputBuffer{limit: limit}
+}
+
+func (b *outputBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	n := len(p)
+	b.written += int64(n)
+
+	if int64(n) >= b.limit {
+		b.buf = append(b.buf[:0], p[int64(n)-b.limit:]...)
+		return n, nil
+	}
+
+	b.buf = append(b.buf, p...)
+	if int64(len(b.buf)) > b.limit {
+		b.buf = b.buf[int64(len(b.buf))-b.limit:]
+	}
+	return n, nil
+}
+
+// Bytes returns a copy of the buffer's current contents - the tail end
+// of everything written, up to limit bytes.
+func (b *outputBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := make([]byte, len(b.buf))
+	copy(out, b.buf)
+	return out
+}
+
+// Truncated reports whether more was written than the buffer could hold.
+func (b *outputBuffer) Truncated() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.written > b.limit
+}
+
+// Written returns the total number of bytes ever written, including
+// bytes that have since fallen out of the buffer.
+func (b *outputBuffer) Written() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.written
+}
+
+// populateOutput copies captured output (if any) from stdoutBuf/stderrBuf
+// into result, for the Config.Stdout/Stderr-nil case where buildCommand
+// wired the command into an outputBuffer instead of the caller's writer.
+func populateOutput(result *Result, stdoutBuf, stderrBuf *outputBuffer) {
+	if stdoutBuf != nil {
+		result.Stdout = stdoutBuf.Bytes()
+		result.StdoutTruncated = stdoutBuf.Truncated()
+		result.StdoutBytesWritten = stdoutBuf.Written()
+	}
+	if stderrBuf != nil {
+		result.Stderr = stderrBuf.Bytes()
+		result.StderrTruncated = stderrBuf.Truncated()
+		result.StderrBytesWritten = stderrBuf.Written()
+	}
+}
@@ -0,0 +1,56 @@
+package sandbox
+
+import "testing"
+
+func TestOutputBufferUnderLimit(t *testing.T) {
+	b := newOutputBuffer(10)
+	b.Write([]byte("hello"))
+
+	if string(b.Bytes()) != "hello" {
+		t.Errorf("Expected %q, got %q", "hello", b.Bytes())
+	}
+	if b.Truncated() {
+		t.Error("Expected not truncated")
+	}
+	if b.Written() != 5 {
+		t.Errorf("Expected Written() == 5, got %d", b.Written())
+	}
+}
+
+func TestOutputBufferTruncatesAcrossWrites(t *testing.T) {
+	b := newOutputBuffer(5)
+	b.Write([]byte("abc"))
+	b.Write([]byte("defgh"))
+
+	if string(b.Bytes()) != "defgh" {
+		t.Errorf("Expected tail %q, got %q", "defgh", b.Bytes())
+	}
+	if !b.Truncated() {
+		t.Error("Expected truncated")
+	}
+	if b.Written() != 8 {
+		t.Errorf("Expected Written() == 8, got %d", b.Written())
+	}
+}
+
+func TestOutputBufferSingleWriteLargerThanLimit(t *testing.T) {
+	b := newOutputBuffer(3)
+	b.Write([]byte("abcdefgh"))
+
+	if string(b.Bytes()) != "fgh" {
+		t.Errorf("Expected tail %q, got %q", "fgh", b.Bytes())
+	}
+	if !b.Truncated() {
+		t.Error("Expected truncated")
+	}
+	if b.Written() != 8 {
+		t.Errorf("Expected Written() == 8, got %d", b.Written())
+	}
+}
+
+func TestOutputBufferDefaultLimit(t *testing.T) {
+	b := newOutputBuffer(0)
+	if b.limit != defaultMaxOutputBytes {
+		t.Errorf("Expected default limit %d, got %d", defaultMaxOutputBytes, b.limit)
+	}
+}
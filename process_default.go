@@ -0,0 +1,110 @@
+// +build darwin !linux,!windows
+
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultProcess is a Process handle for a command started with
+// DefaultSandbox.Start. This platform has no cgroup/job-object
+// equivalent, so Exec just starts an independent sibling sandboxed with
+// the same Config, Stats is unsupported, and Pids only ever reports this
+// one process.
+type DefaultProcess struct {
+	sandbox *DefaultSandbox
+	cmd     *exec.Cmd
+
+	stdoutBuf *outputBuffer
+	stderrBuf *outputBuffer
+
+	mu        sync.Mutex
+	startTime time.Time
+	waited    bool
+	result    *Result
+}
+
+// Start launches command in the sandbox without waiting for it to exit.
+func (s *DefaultSandbox) Start(ctx context.Context, command string, args ...string) (Process, error) {
+	cmd, stdoutBuf, stderrBuf, err := s.buildCommand(ctx, command, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start process: %w", err)
+	}
+
+	return &DefaultProcess{
+		sandbox:   s,
+		cmd:       cmd,
+		stdoutBuf: stdoutBuf,
+		stderrBuf: stderrBuf,
+		startTime: time.Now(),
+	}, nil
+}
+
+// Pid returns the OS process ID of the process Start was called with.
+func (p *DefaultProcess) Pid() int {
+	return p.cmd.Process.Pid
+}
+
+// Signal delivers sig to the process.
+func (p *DefaultProcess) Signal(sig os.Signal) error {
+	return p.cmd.Process.Signal(sig)
+}
+
+// Wait blocks until the process exits and returns its Result.
+func (p *DefaultProcess) Wait() (*Result, error) {
+	p.mu.Lock()
+	if p.waited {
+		defer p.mu.Unlock()
+		return p.result, nil
+	}
+	p.waited = true
+	p.mu.Unlock()
+
+	result := &Result{}
+	err := p.cmd.Wait()
+	result.ExecutionTime = time.Since(p.startTime)
+
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			result.ExitCode = ws.ExitStatus()
+		}
+	} else if err == nil {
+		result.ExitCode = 0
+	}
+
+	populateRusage(result, p.cmd.ProcessState)
+	populateOutput(result, p.stdoutBuf, p.stderrBuf)
+
+	p.mu.Lock()
+	p.result = result
+	p.mu.Unlock()
+
+	return result, nil
+}
+
+// Exec starts an additional, independently sandboxed command - this
+// platform has no shared job/cgroup primitive to join it to.
+func (p *DefaultProcess) Exec(ctx context.Context, command string, args ...string) (Process, error) {
+	return p.sandbox.Start(ctx, command, args...)
+}
+
+// Stats isn't supported on this platform: there's no cgroup or job
+// object to sample usage from.
+func (p *DefaultProcess) Stats() (*Stats, error) {
+	return nil, fmt.Errorf("sandbox: Stats is not supported on this platform")
+}
+
+// Pids reports only this process, as there is no job grouping here.
+func (p *DefaultProcess) Pids() ([]int, error) {
+	return []int{p.Pid()}, nil
+}
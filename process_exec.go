@@ -0,0 +1,80 @@
+// +build linux
+
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// execProcess is a Process handle for drivers (gvisor, bwrap) that hand
+// off the whole job to an external runtime binary rather than managing a
+// cgroup or job object themselves. Without that shared grouping
+// primitive, Exec just starts an independent sibling and Stats isn't
+// available.
+type execProcess struct {
+	cmd         *exec.Cmd
+	startFn     func(ctx context.Context, command string, args ...string) (Process, error)
+	cleanupFunc func()
+
+	mu        sync.Mutex
+	startTime time.Time
+	waited    bool
+	result    *Result
+}
+
+func (p *execProcess) Pid() int {
+	return p.cmd.Process.Pid
+}
+
+func (p *execProcess) Signal(sig os.Signal) error {
+	return p.cmd.Process.Signal(sig)
+}
+
+func (p *execProcess) Wait() (*Result, error) {
+	p.mu.Lock()
+	if p.waited {
+		defer p.mu.Unlock()
+		return p.result, nil
+	}
+	p.waited = true
+	p.mu.Unlock()
+
+	if p.cleanupFunc != nil {
+		defer p.cleanupFunc()
+	}
+
+	result := &Result{}
+	err := p.cmd.Wait()
+	result.ExecutionTime = time.Since(p.startTime)
+
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		result.ExitCode = exitErr.ExitCode()
+	} else if err == nil {
+		result.ExitCode = 0
+	} else {
+		result.Error = err
+	}
+
+	p.mu.Lock()
+	p.result = result
+	p.mu.Unlock()
+
+	return result, nil
+}
+
+func (p *execProcess) Exec(ctx context.Context, command string, args ...string) (Process, error) {
+	return p.startFn(ctx, command, args...)
+}
+
+func (p *execProcess) Stats() (*Stats, error) {
+	return nil, fmt.Errorf("sandbox: Stats is not supported by this driver")
+}
+
+func (p *execProcess) Pids() ([]int, error) {
+	return []int{p.Pid()}, nil
+}
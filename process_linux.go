@@ -0,0 +1,205 @@
+// +build linux
+
+package sandbox
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// LinuxProcess is a Process handle for a command started with
+// LinuxSandbox.Start. It keeps the sandbox's cgroup alive so additional
+// commands can join it via Exec and its resource usage can be sampled
+// via Stats, the same lifecycle containerd gives a running container.
+type LinuxProcess struct {
+	sandbox *LinuxSandbox
+	cmd     *exec.Cmd
+	sampler *eventSampler
+
+	stdoutBuf *outputBuffer
+	stderrBuf *outputBuffer
+
+	mu        sync.Mutex
+	startTime time.Time
+	waited    bool
+	result    *Result
+	waitErr   error
+}
+
+// Start launches command in the sandbox without waiting for it to exit.
+func (s *LinuxSandbox) Start(ctx context.Context, command string, args ...string) (Process, error) {
+	s.setupCgroup()
+
+	cmd, stdoutBuf, stderrBuf, err := s.buildCommand(ctx, command, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start process: %w", err)
+	}
+
+	if s.cgroupPath != "" && s.useCgroupV2 {
+		s.addPidToCgroup(cmd.Process.Pid)
+	}
+
+	return &LinuxProcess{
+		sandbox:   s,
+		cmd:       cmd,
+		sampler:   newEventSampler(s, cmd.Process.Pid),
+		stdoutBuf: stdoutBuf,
+		stderrBuf: stderrBuf,
+		startTime: time.Now(),
+	}, nil
+}
+
+// addPidToCgroup joins pid to the sandbox's cgroup, best-effort (the
+// cgroup may not exist if setupCgroup couldn't create one, e.g. when
+// running unprivileged).
+func (s *LinuxSandbox) addPidToCgroup(pid int) {
+	procsPath := filepath.Join(s.cgroupPath, "cgroup.procs")
+	os.WriteFile(procsPath, []byte(strconv.Itoa(pid)), 0644)
+}
+
+// Pid returns the OS process ID of the process Start was called with.
+func (p *LinuxProcess) Pid() int {
+	return p.cmd.Process.Pid
+}
+
+// Signal delivers sig to the process.
+func (p *LinuxProcess) Signal(sig os.Signal) error {
+	return p.cmd.Process.Signal(sig)
+}
+
+// Wait blocks until the process exits and returns its Result.
+func (p *LinuxProcess) Wait() (*Result, error) {
+	p.mu.Lock()
+	if p.waited {
+		defer p.mu.Unlock()
+		return p.result, p.waitErr
+	}
+	p.waited = true
+	p.mu.Unlock()
+
+	result := &Result{}
+	err := p.cmd.Wait()
+	result.ExecutionTime = time.Since(p.startTime)
+
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			result.ExitCode = status.ExitStatus()
+		}
+	} else if err == nil {
+		result.ExitCode = 0
+	}
+
+	populateRusage(result, p.cmd.ProcessState)
+	populateOutput(result, p.stdoutBuf, p.stderrBuf)
+
+	if p.sampler != nil {
+		p.sampler.Stop(p.Pid())
+		p.sampler.populateResult(result)
+	}
+
+	p.mu.Lock()
+	p.result, p.waitErr = result, nil
+	p.mu.Unlock()
+
+	return result, nil
+}
+
+// Exec starts an additional command inside the same cgroup as p.
+func (p *LinuxProcess) Exec(ctx context.Context, command string, args ...string) (Process, error) {
+	return p.sandbox.Start(ctx, command, args...)
+}
+
+// Stats samples current resource usage for the sandbox's cgroup.
+func (p *LinuxProcess) Stats() (*Stats, error) {
+	if p.sandbox.cgroupPath == "" || !p.sandbox.useCgroupV2 {
+		return nil, fmt.Errorf("cgroup v2 not available for this sandbox")
+	}
+
+	stats := &Stats{}
+
+	if data, err := os.ReadFile(filepath.Join(p.sandbox.cgroupPath, "cpu.stat")); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) == 2 && fields[0] == "usage_usec" {
+				if usec, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+					stats.CPUTime = time.Duration(usec) * time.Microsecond
+				}
+			}
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(p.sandbox.cgroupPath, "memory.current")); err == nil {
+		if mem, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); err == nil {
+			stats.MemoryUsageBytes = mem
+		}
+	}
+
+	if f, err := os.Open(filepath.Join(p.sandbox.cgroupPath, "io.stat")); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			for _, kv := range strings.Fields(scanner.Text())[1:] {
+				parts := strings.SplitN(kv, "=", 2)
+				if len(parts) != 2 {
+					continue
+				}
+				val, err := strconv.ParseInt(parts[1], 10, 64)
+				if err != nil {
+					continue
+				}
+				switch parts[0] {
+				case "rbytes":
+					stats.IOReadBytes += val
+				case "wbytes":
+					stats.IOWriteBytes += val
+				}
+			}
+		}
+		f.Close()
+	}
+
+	pids, err := p.Pids()
+	if err == nil {
+		stats.NumPIDs = len(pids)
+	}
+
+	return stats, nil
+}
+
+// Pids lists the OS process IDs currently running in the sandbox's
+// cgroup, read from cgroup.procs.
+func (p *LinuxProcess) Pids() ([]int, error) {
+	if p.sandbox.cgroupPath == "" || !p.sandbox.useCgroupV2 {
+		return []int{p.Pid()}, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(p.sandbox.cgroupPath, "cgroup.procs"))
+	if err != nil {
+		return nil, fmt.Errorf("reading cgroup.procs: %w", err)
+	}
+
+	var pids []int
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+		pid, err := strconv.Atoi(line)
+		if err != nil {
+			continue
+		}
+		pids = append(pids, pid)
+	}
+	return pids, nil
+}
@@ -0,0 +1,86 @@
+// +build linux
+
+package sandbox
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLinuxSandboxStartWaitExec(t *testing.T) {
+	config := &Config{
+		Timeout:     5 * time.Second,
+		MemoryLimit: 100 * 1024 * 1024,
+	}
+	sb, err := New(config)
+	if err != nil {
+		t.Fatalf("Failed to create sandbox: %v", err)
+	}
+	defer sb.Cleanup()
+
+	proc, err := sb.Start(context.Background(), "sh", "-c", "exit 0")
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	if proc.Pid() <= 0 {
+		t.Errorf("Expected positive Pid, got %d", proc.Pid())
+	}
+
+	pids, err := proc.Pids()
+	if err != nil {
+		t.Fatalf("Pids failed: %v", err)
+	}
+	if len(pids) == 0 {
+		t.Error("Expected at least one pid")
+	}
+
+	result, err := proc.Wait()
+	if err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if result.ExitCode != 0 {
+		t.Errorf("Expected exit code 0, got %d", result.ExitCode)
+	}
+
+	second, err := proc.Exec(context.Background(), "sh", "-c", "exit 0")
+	if err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if _, err := second.Wait(); err != nil {
+		t.Fatalf("Wait on Exec'd process failed: %v", err)
+	}
+}
+
+func TestLinuxSandboxWaitIsIdempotent(t *testing.T) {
+	config := &Config{
+		Timeout:     5 * time.Second,
+		MemoryLimit: 100 * 1024 * 1024,
+	}
+	sb, err := New(config)
+	if err != nil {
+		t.Fatalf("Failed to create sandbox: %v", err)
+	}
+	defer sb.Cleanup()
+
+	proc, err := sb.Start(context.Background(), "sh", "-c", "exit 3")
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	first, err := proc.Wait()
+	if err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	second, err := proc.Wait()
+	if err != nil {
+		t.Fatalf("Second Wait failed: %v", err)
+	}
+	if first != second {
+		t.Error("Expected second Wait to return the same cached Result")
+	}
+	if second.ExitCode != 3 {
+		t.Errorf("Expected exit code 3, got %d", second.ExitCode)
+	}
+}
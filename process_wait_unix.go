@@ -0,0 +1,40 @@
+// +build !windows
+
+package sandbox
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// waitOrStop waits for cmd to exit. If ctx is done first, it sends sig to
+// cmd's process group and gives it killDelay to exit on its own before
+// escalating to SIGKILL, modeled on the Go Playground's
+// internal.WaitOrStop. forced reports which happened: false if cmd
+// exited (on its own, or after sig) within killDelay, true if SIGKILL
+// was needed.
+func waitOrStop(ctx context.Context, cmd *exec.Cmd, sig syscall.Signal, killDelay time.Duration) (err error, forced bool) {
+	errc := make(chan error, 1)
+	go func() { errc <- cmd.Wait() }()
+
+	select {
+	case err = <-errc:
+		return err, false
+	case <-ctx.Done():
+	}
+
+	syscall.Kill(-cmd.Process.Pid, sig)
+
+	timer := time.NewTimer(killDelay)
+	defer timer.Stop()
+	select {
+	case err = <-errc:
+		return err, false
+	case <-timer.C:
+	}
+
+	syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	return <-errc, true
+}
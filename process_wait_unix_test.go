@@ -0,0 +1,43 @@
+// +build !windows
+
+package sandbox
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestWaitOrStopGraceful(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "trap 'exit 0' TERM; sleep 5")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start process: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	_, forced := waitOrStop(ctx, cmd, syscall.SIGTERM, 5*time.Second)
+	if forced {
+		t.Error("Expected process to exit gracefully on SIGTERM, not be forced")
+	}
+}
+
+func TestWaitOrStopForced(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "trap '' TERM; sleep 5")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start process: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	_, forced := waitOrStop(ctx, cmd, syscall.SIGTERM, 300*time.Millisecond)
+	if !forced {
+		t.Error("Expected process ignoring SIGTERM to be force-killed")
+	}
+}
@@ -0,0 +1,181 @@
+// +build windows
+
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// WindowsProcess is a Process handle for a command started with
+// WindowsSandbox.Start. Additional commands joined via Exec are assigned
+// to the same Job Object, so Stats/Pids report the whole job rather than
+// just this one process.
+type WindowsProcess struct {
+	sandbox *WindowsSandbox
+	cmd     *exec.Cmd
+
+	stdoutBuf *outputBuffer
+	stderrBuf *outputBuffer
+
+	mu        sync.Mutex
+	startTime time.Time
+	waited    bool
+	result    *Result
+}
+
+// Start launches command in the sandbox without waiting for it to exit.
+func (s *WindowsSandbox) Start(ctx context.Context, command string, args ...string) (Process, error) {
+	if s.jobHandle == 0 {
+		if err := s.createJobObject(); err != nil {
+			return nil, err
+		}
+	}
+
+	cmd, stdoutBuf, stderrBuf := s.buildCommand(ctx, command, args...)
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start process: %w", err)
+	}
+
+	ret, _, err := procAssignProcessToJobObject.Call(uintptr(s.jobHandle), uintptr(cmd.Process.Pid))
+	if ret == 0 {
+		cmd.Process.Kill()
+		return nil, fmt.Errorf("failed to assign process to job: %v", err)
+	}
+
+	return &WindowsProcess{
+		sandbox:   s,
+		cmd:       cmd,
+		stdoutBuf: stdoutBuf,
+		stderrBuf: stderrBuf,
+		startTime: time.Now(),
+	}, nil
+}
+
+// Pid returns the OS process ID of the process Start was called with.
+func (p *WindowsProcess) Pid() int {
+	return p.cmd.Process.Pid
+}
+
+// Signal delivers sig to the process. Windows only supports
+// os.Kill-style termination through os.Process.Signal.
+func (p *WindowsProcess) Signal(sig os.Signal) error {
+	return p.cmd.Process.Signal(sig)
+}
+
+// Wait blocks until the process exits and returns its Result.
+func (p *WindowsProcess) Wait() (*Result, error) {
+	p.mu.Lock()
+	if p.waited {
+		defer p.mu.Unlock()
+		return p.result, nil
+	}
+	p.waited = true
+	p.mu.Unlock()
+
+	result := &Result{}
+	err := p.cmd.Wait()
+	result.ExecutionTime = time.Since(p.startTime)
+
+	if p.cmd.ProcessState != nil {
+		result.ExitCode = p.cmd.ProcessState.ExitCode()
+	}
+	_ = err
+
+	populateOutput(result, p.stdoutBuf, p.stderrBuf)
+
+	p.mu.Lock()
+	p.result = result
+	p.mu.Unlock()
+
+	return result, nil
+}
+
+// Exec starts an additional command in the same Job Object as p.
+func (p *WindowsProcess) Exec(ctx context.Context, command string, args ...string) (Process, error) {
+	return p.sandbox.Start(ctx, command, args...)
+}
+
+// Stats samples current resource usage for the sandbox's Job Object via
+// JOBOBJECT_BASIC_ACCOUNTING_INFORMATION and JOBOBJECT_EXTENDED_LIMIT_INFORMATION.
+// MemoryUsageBytes holds the job's peak memory use (PeakJobMemoryUsed),
+// not its current use.
+func (p *WindowsProcess) Stats() (*Stats, error) {
+	var accounting JOBOBJECT_BASIC_ACCOUNTING_INFORMATION
+	ret, _, err := procQueryInformationJobObject.Call(
+		uintptr(p.sandbox.jobHandle),
+		uintptr(JobObjectBasicAccountingInformation),
+		uintptr(unsafe.Pointer(&accounting)),
+		unsafe.Sizeof(accounting),
+		0,
+	)
+	if ret == 0 {
+		return nil, fmt.Errorf("QueryInformationJobObject: %v", err)
+	}
+
+	var limits JOBOBJECT_EXTENDED_LIMIT_INFORMATION
+	ret, _, err = procQueryInformationJobObject.Call(
+		uintptr(p.sandbox.jobHandle),
+		uintptr(JobObjectExtendedLimitInformation),
+		uintptr(unsafe.Pointer(&limits)),
+		unsafe.Sizeof(limits),
+		0,
+	)
+	if ret == 0 {
+		return nil, fmt.Errorf("QueryInformationJobObject: %v", err)
+	}
+
+	pids, err := p.Pids()
+	if err != nil {
+		return nil, err
+	}
+
+	// Windows reports times in 100-nanosecond intervals.
+	cpuTime := time.Duration(accounting.TotalUserTime+accounting.TotalKernelTime) * 100 * time.Nanosecond
+
+	return &Stats{
+		CPUTime:          cpuTime,
+		MemoryUsageBytes: int64(limits.PeakJobMemoryUsed),
+		IOReadBytes:      int64(limits.IoInfo.ReadTransferCount),
+		IOWriteBytes:     int64(limits.IoInfo.WriteTransferCount),
+		NumPIDs:          len(pids),
+	}, nil
+}
+
+// Pids lists the OS process IDs currently assigned to the sandbox's Job
+// Object, read via JobObjectBasicProcessIdList.
+func (p *WindowsProcess) Pids() ([]int, error) {
+	// Query once, with room for maxPids entries; a job holding more
+	// processes than that reports only the first maxPids of them.
+	header := jobObjectBasicProcessIDListHeader{}
+	const maxPids = 1024
+	bufSize := unsafe.Sizeof(header) + uintptr(maxPids)*unsafe.Sizeof(uintptr(0))
+	buf := make([]byte, bufSize)
+
+	ret, _, err := procQueryInformationJobObject.Call(
+		uintptr(p.sandbox.jobHandle),
+		uintptr(JobObjectBasicProcessIdList),
+		uintptr(unsafe.Pointer(&buf[0])),
+		bufSize,
+		0,
+	)
+	if ret == 0 {
+		return nil, fmt.Errorf("QueryInformationJobObject(ProcessIdList): %v", err)
+	}
+
+	hdr := (*jobObjectBasicProcessIDListHeader)(unsafe.Pointer(&buf[0]))
+	idsPtr := uintptr(unsafe.Pointer(&buf[0])) + unsafe.Sizeof(header)
+
+	pids := make([]int, 0, hdr.NumberOfProcessIdsInList)
+	for i := uint32(0); i < hdr.NumberOfProcessIdsInList; i++ {
+		id := *(*uintptr)(unsafe.Pointer(idsPtr + uintptr(i)*unsafe.Sizeof(uintptr(0))))
+		pids = append(pids, int(id))
+	}
+	return pids, nil
+}
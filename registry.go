@@ -0,0 +1,88 @@
+package sandbox
+
+import (
+	"fmt"
+	"sync"
+)
+
+// DriverFactory constructs a Sandbox instance for a registered driver
+// name.
+type DriverFactory func(*Config) (Sandbox, error)
+
+// DriverCapabilities describes what a driver backend actually enforces,
+// so callers can check whether a driver is strong enough for a given
+// workload (e.g. untrusted code needing seccomp and network isolation)
+// before submitting it.
+type DriverCapabilities struct {
+	// MemoryLimit is true if the driver enforces Config.MemoryLimit.
+	MemoryLimit bool
+
+	// CPULimit is true if the driver enforces Config.CPULimit.
+	CPULimit bool
+
+	// Seccomp is true if the driver honors Config.SeccompProfile.
+	Seccomp bool
+
+	// NamespaceIsolation is true if the driver honors Config.Namespaces
+	// and Config.RootFS.
+	NamespaceIsolation bool
+
+	// NetworkIsolation is true if the driver can disable network access
+	// (Config.NetworkAccess = false).
+	NetworkIsolation bool
+
+	// Hypervisor is true for drivers that run the command in its own
+	// virtual machine rather than sharing the host kernel.
+	Hypervisor bool
+}
+
+type driverRegistration struct {
+	factory      DriverFactory
+	capabilities DriverCapabilities
+}
+
+var (
+	driversMu sync.RWMutex
+	drivers   = map[string]driverRegistration{}
+)
+
+// Register adds a named sandbox driver so it can be selected via
+// NewWithDriver or Config.Driver. Registering a name that's already
+// registered replaces the existing entry - this lets a program swap in
+// its own "native" driver for tests.
+func Register(name string, factory DriverFactory, capabilities DriverCapabilities) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	drivers[name] = driverRegistration{factory: factory, capabilities: capabilities}
+}
+
+// NewWithDriver creates a Sandbox using the named driver.
+func NewWithDriver(name string, config *Config) (Sandbox, error) {
+	driversMu.RLock()
+	reg, ok := drivers[name]
+	driversMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("sandbox: unknown driver %q", name)
+	}
+	return reg.factory(config)
+}
+
+// Capabilities reports what the named driver enforces. The second
+// return value is false if the driver isn't registered.
+func Capabilities(name string) (DriverCapabilities, bool) {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	reg, ok := drivers[name]
+	return reg.capabilities, ok
+}
+
+// Drivers lists the names of every registered driver.
+func Drivers() []string {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	return names
+}
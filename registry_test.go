@@ -0,0 +1,73 @@
+package sandbox
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestNewWithDriverNativeRoundTrip(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	caps, ok := Capabilities("native")
+	if !ok {
+		t.Fatal("Expected \"native\" driver to be registered")
+	}
+	if !caps.MemoryLimit || !caps.CPULimit {
+		t.Errorf("Expected native driver to advertise MemoryLimit/CPULimit, got %+v", caps)
+	}
+
+	config := &Config{
+		Timeout:     5 * time.Second,
+		MemoryLimit: 100 * 1024 * 1024,
+	}
+	sb, err := NewWithDriver("native", config)
+	if err != nil {
+		t.Fatalf("NewWithDriver failed: %v", err)
+	}
+	defer sb.Cleanup()
+
+	result, err := sb.Run(context.Background(), "sh", "-c", "exit 0")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.ExitCode != 0 {
+		t.Errorf("Expected exit code 0, got %d", result.ExitCode)
+	}
+}
+
+func TestNewWithDriverUnknown(t *testing.T) {
+	if _, err := NewWithDriver("nonexistent-driver", &Config{}); err == nil {
+		t.Error("Expected an error for an unregistered driver name")
+	}
+}
+
+func TestCapabilitiesUnknownDriver(t *testing.T) {
+	if _, ok := Capabilities("nonexistent-driver"); ok {
+		t.Error("Expected ok=false for an unregistered driver name")
+	}
+}
+
+func TestConfigDriverSelectsRegisteredDriver(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	config := &Config{
+		Driver:      "native",
+		Timeout:     5 * time.Second,
+		MemoryLimit: 100 * 1024 * 1024,
+	}
+	sb, err := New(config)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer sb.Cleanup()
+
+	if _, err := sb.Run(context.Background(), "sh", "-c", "exit 0"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+}
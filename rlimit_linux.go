@@ -0,0 +1,39 @@
+// +build linux
+
+package sandbox
+
+import (
+	"fmt"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// rlimitNproc is RLIMIT_NPROC. The stdlib syscall package omits it on
+// linux (unlike RLIMIT_AS/DATA/STACK/CPU/FSIZE/CORE, which it defines
+// directly), so it's hardcoded here from linux's bits/resource.h.
+const rlimitNproc = 6
+
+// itimerval mirrors the C struct itimerval passed to setitimer(2).
+type itimerval struct {
+	IntervalSec  int64
+	IntervalUsec int64
+	ValueSec     int64
+	ValueUsec    int64
+}
+
+// installRealTimeLimit arms an ITIMER_REAL alarm that delivers SIGALRM
+// after d, the same exec-surviving-timer trick sandboxmain_unix.go's
+// installRealTimeLimit uses for DefaultSandbox. Unlike that darwin
+// version, no syscall number needs to be hardcoded here: the stdlib
+// syscall package exposes SYS_SETITIMER directly on linux.
+func installRealTimeLimit(d int64) error {
+	it := itimerval{
+		ValueSec:  d / int64(time.Second),
+		ValueUsec: (d % int64(time.Second)) / int64(time.Microsecond),
+	}
+	if _, _, errno := syscall.Syscall(syscall.SYS_SETITIMER, 0, uintptr(unsafe.Pointer(&it)), 0); errno != 0 {
+		return fmt.Errorf("setitimer(ITIMER_REAL): %w", errno)
+	}
+	return nil
+}
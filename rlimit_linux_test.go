@@ -0,0 +1,33 @@
+// +build linux
+
+package sandbox
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestFileSizeLimitEnforced(t *testing.T) {
+	config := &Config{
+		Timeout:       5 * time.Second,
+		MemoryLimit:   100 * 1024 * 1024,
+		FileSizeLimit: 1024,
+	}
+	sb, err := New(config)
+	if err != nil {
+		t.Fatalf("Failed to create sandbox: %v", err)
+	}
+	defer sb.Cleanup()
+	defer os.Remove("/tmp/go-proc-sandbox-fsize-test")
+
+	result, err := sb.Run(context.Background(), "dd", "if=/dev/zero", "of=/tmp/go-proc-sandbox-fsize-test", "bs=1024", "count=64")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if !result.FileSizeExceeded {
+		t.Errorf("Expected FileSizeExceeded, got Result %+v", result)
+	}
+}
@@ -0,0 +1,60 @@
+// +build !windows
+
+package sandbox
+
+import "syscall"
+
+// rlimitInfinity is RLIM_INFINITY: all bits set, as defined by POSIX and
+// used by every platform this file builds on.
+const rlimitInfinity = ^uint64(0)
+
+// rlimitTrampolineEnv carries the JSON-encoded rlimitSetup a program's
+// re-exec'd instance needs to apply to itself before exec'ing the real
+// target. See sandboxmain_unix.go's SandboxMain (DefaultSandbox) and
+// seccomp_linux.go's init (LinuxSandbox), which is always-on rather
+// than requiring an explicit call.
+const rlimitTrampolineEnv = "GO_PROC_SANDBOX_RLIMITS"
+
+// rlimitSetup is the set of rlimits DefaultSandbox and LinuxSandbox ask
+// their respective trampolines to apply. For every field except Core,
+// zero means "leave that limit alone" and a negative value means
+// "unlimited". Core is always applied since its zero value (disable
+// core dumps) is itself a meaningful limit, not an absence of one.
+type rlimitSetup struct {
+	AS       int64
+	Data     int64
+	Stack    int64
+	NProc    int64
+	FSize    int64
+	CPU      int64
+	Core     int64
+	RealTime int64 // nanoseconds; 0 disables the SIGALRM deadline
+}
+
+// applyRlimit sets resource to limit on the current process. limit == 0
+// means "leave it alone" - 0 is never a useful real limit for any of the
+// resources the rlimit trampolines manage this way. limit < 0 sets the
+// resource unlimited (RLIM_INFINITY), which matters for StackLimit's -1
+// meaning.
+func applyRlimit(resource int, limit int64) {
+	if limit == 0 {
+		return
+	}
+	syscall.Setrlimit(resource, rlimitValue(limit))
+}
+
+// applyCoreLimit sets RLIMIT_CORE unconditionally, since unlike the
+// other rlimits here its zero value (disable core dumps) is itself the
+// default we want applied, not an "unset" sentinel.
+func applyCoreLimit(limit int64) {
+	syscall.Setrlimit(syscall.RLIMIT_CORE, rlimitValue(limit))
+}
+
+// rlimitValue converts limit into a syscall.Rlimit, treating a negative
+// limit as unlimited.
+func rlimitValue(limit int64) *syscall.Rlimit {
+	if limit < 0 {
+		return &syscall.Rlimit{Cur: rlimitInfinity, Max: rlimitInfinity}
+	}
+	return &syscall.Rlimit{Cur: uint64(limit), Max: uint64(limit)}
+}
@@ -0,0 +1,58 @@
+// +build !windows
+
+package sandbox
+
+import (
+	"syscall"
+	"testing"
+)
+
+func TestRlimitValue(t *testing.T) {
+	got := rlimitValue(1024)
+	if got.Cur != 1024 || got.Max != 1024 {
+		t.Errorf("Expected {1024 1024}, got %+v", got)
+	}
+
+	unlimited := rlimitValue(-1)
+	if unlimited.Cur != rlimitInfinity || unlimited.Max != rlimitInfinity {
+		t.Errorf("Expected a negative limit to map to RLIM_INFINITY, got %+v", unlimited)
+	}
+}
+
+func TestApplyRlimitZeroIsNoop(t *testing.T) {
+	var before syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_FSIZE, &before); err != nil {
+		t.Fatalf("Getrlimit failed: %v", err)
+	}
+
+	applyRlimit(syscall.RLIMIT_FSIZE, 0)
+
+	var after syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_FSIZE, &after); err != nil {
+		t.Fatalf("Getrlimit failed: %v", err)
+	}
+	if before != after {
+		t.Errorf("Expected limit 0 to leave RLIMIT_FSIZE unchanged, was %+v now %+v", before, after)
+	}
+}
+
+func TestApplyRlimitSetsLimit(t *testing.T) {
+	var before syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_FSIZE, &before); err != nil {
+		t.Fatalf("Getrlimit failed: %v", err)
+	}
+	if before.Cur != rlimitInfinity && before.Cur < 1<<20 {
+		t.Skip("current RLIMIT_FSIZE is already below the value this test sets")
+	}
+
+	applyRlimit(syscall.RLIMIT_FSIZE, 1<<20)
+	defer syscall.Setrlimit(syscall.RLIMIT_FSIZE, &before)
+
+	var after syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_FSIZE, &after); err != nil {
+		t.Fatalf("Getrlimit failed: %v", err)
+	}
+	if after.Cur != 1<<20 {
+		t.Errorf("Expected RLIMIT_FSIZE.Cur == %d, got %d", 1<<20, after.Cur)
+	}
+}
@@ -0,0 +1,88 @@
+package sandbox
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Runner runs commands through a shared Config while capping how many
+// run concurrently, the same role golang.org/x/tools/internal/gocommand.
+// Runner plays for go command invocations. Services that fan a lot of
+// sandboxed jobs out across goroutines can use one Runner instead of
+// each caller inventing its own worker pool.
+type Runner struct {
+	config    *Config
+	inFlight  chan struct{}
+	completed int64
+}
+
+// NewRunner creates a Runner that runs commands using config, allowing
+// at most maxInFlight to execute concurrently. maxInFlight <= 0 is
+// treated as 1.
+func NewRunner(config *Config, maxInFlight int) *Runner {
+	if maxInFlight <= 0 {
+		maxInFlight = 1
+	}
+	return &Runner{
+		config:   config,
+		inFlight: make(chan struct{}, maxInFlight),
+	}
+}
+
+// Run claims a slot in the in-flight semaphore, blocking until one is
+// free or ctx is done, then builds a fresh Sandbox from the Runner's
+// Config and runs command through it.
+func (r *Runner) Run(ctx context.Context, command string, args ...string) (*Result, error) {
+	select {
+	case r.inFlight <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-r.inFlight }()
+
+	sb, err := New(r.cloneConfig())
+	if err != nil {
+		return nil, err
+	}
+	defer sb.Cleanup()
+
+	result, err := sb.Run(ctx, command, args...)
+	atomic.AddInt64(&r.completed, 1)
+	return result, err
+}
+
+// cloneConfig returns a shallow copy of the Runner's Config. The
+// platform constructors New ends up calling (NewLinuxSandbox et al.)
+// fill in defaults by mutating the *Config they're given in place;
+// since Runner exists specifically so multiple goroutines can call Run
+// concurrently, handing them all the same *Config would race on those
+// writes. Nil passes through unchanged - New already handles a nil
+// Config safely, fresh per call.
+func (r *Runner) cloneConfig() *Config {
+	if r.config == nil {
+		return nil
+	}
+	cfg := *r.config
+	return &cfg
+}
+
+// RunnerStats reports a Runner's current activity.
+type RunnerStats struct {
+	// InFlight is the number of Run calls currently executing.
+	InFlight int
+
+	// Completed is the total number of Run calls that ran their command
+	// through a Sandbox, successfully or not, since the Runner was
+	// created. Calls that return before that (ctx done while waiting for
+	// a slot, or New failing) are not counted.
+	Completed int64
+}
+
+// Stats reports the Runner's current in-flight count and total
+// completed runs.
+func (r *Runner) Stats() RunnerStats {
+	return RunnerStats{
+		InFlight:  len(r.inFlight),
+		Completed: atomic.LoadInt64(&r.completed),
+	}
+}
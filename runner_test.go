@@ -0,0 +1,87 @@
+package sandbox
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunnerLimitsConcurrency(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	config := &Config{
+		Timeout:     5 * time.Second,
+		MemoryLimit: 100 * 1024 * 1024,
+	}
+	runner := NewRunner(config, 2)
+
+	done := make(chan struct{})
+	var maxSeen int64
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if n := int64(runner.Stats().InFlight); n > atomic.LoadInt64(&maxSeen) {
+				atomic.StoreInt64(&maxSeen, n)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runner.Run(context.Background(), "sh", "-c", "sleep 0.1")
+		}()
+	}
+	wg.Wait()
+	close(done)
+
+	if got := atomic.LoadInt64(&maxSeen); got > 2 {
+		t.Errorf("Expected at most 2 concurrent runs, saw %d", got)
+	} else if got < 2 {
+		t.Errorf("Expected the semaphore's InFlight to reach 2, saw %d", got)
+	}
+
+	stats := runner.Stats()
+	if stats.Completed != 6 {
+		t.Errorf("Expected 6 completed runs, got %d", stats.Completed)
+	}
+	if stats.InFlight != 0 {
+		t.Errorf("Expected 0 in-flight after Wait, got %d", stats.InFlight)
+	}
+}
+
+func TestRunnerRunDoesNotRaceConfig(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	config := &Config{
+		Timeout:     5 * time.Second,
+		MemoryLimit: 100 * 1024 * 1024,
+	}
+	runner := NewRunner(config, 4)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := runner.Run(context.Background(), "sh", "-c", "exit 0"); err != nil {
+				t.Errorf("Run failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
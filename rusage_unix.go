@@ -0,0 +1,39 @@
+// +build !windows
+
+package sandbox
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// populateRusage fills result's rusage fields from ps, the same
+// getrusage(2) telemetry Nomad's executor surfaces without requiring
+// cgroups. Maxrss is reported in bytes on darwin but kilobytes
+// everywhere else this file builds for, so it's normalized here.
+func populateRusage(result *Result, ps *os.ProcessState) {
+	if ps == nil {
+		return
+	}
+	ru, ok := ps.SysUsage().(*syscall.Rusage)
+	if !ok || ru == nil {
+		return
+	}
+
+	maxRSS := ru.Maxrss
+	if runtime.GOOS != "darwin" {
+		maxRSS *= 1024
+	}
+
+	result.UserCPUTime = time.Duration(ru.Utime.Nano())
+	result.SystemCPUTime = time.Duration(ru.Stime.Nano())
+	result.MaxRSSBytes = maxRSS
+	result.MinorPageFaults = int64(ru.Minflt)
+	result.MajorPageFaults = int64(ru.Majflt)
+	result.VoluntaryCtxSwitches = int64(ru.Nvcsw)
+	result.InvoluntaryCtxSwitches = int64(ru.Nivcsw)
+	result.IOBlocksIn = int64(ru.Inblock)
+	result.IOBlocksOut = int64(ru.Oublock)
+}
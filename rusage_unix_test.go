@@ -0,0 +1,38 @@
+// +build !windows
+
+package sandbox
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestRunPopulatesRusage(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	config := &Config{
+		Timeout:     5 * time.Second,
+		MemoryLimit: 100 * 1024 * 1024,
+	}
+	sb, err := New(config)
+	if err != nil {
+		t.Fatalf("Failed to create sandbox: %v", err)
+	}
+	defer sb.Cleanup()
+
+	result, err := sb.Run(context.Background(), "sh", "-c", "echo hi")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if result.MaxRSSBytes <= 0 {
+		t.Errorf("Expected MaxRSSBytes > 0, got %d", result.MaxRSSBytes)
+	}
+	if result.UserCPUTime < 0 || result.SystemCPUTime < 0 {
+		t.Errorf("Expected non-negative CPU times, got user=%v system=%v", result.UserCPUTime, result.SystemCPUTime)
+	}
+}
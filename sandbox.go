@@ -3,6 +3,8 @@ package sandbox
 import (
 	"context"
 	"io"
+	"os"
+	"syscall"
 	"time"
 )
 
@@ -17,6 +19,14 @@ type Config struct {
 	// Execution timeout
 	Timeout time.Duration
 
+	// InterruptSignal is sent to the process group when Timeout expires,
+	// before escalating to SIGKILL. Defaults to SIGTERM.
+	InterruptSignal syscall.Signal
+
+	// KillDelay is how long to wait after InterruptSignal before
+	// escalating to SIGKILL. Defaults to 2s.
+	KillDelay time.Duration
+
 	// Working directory for the process
 	WorkingDir string
 
@@ -38,11 +48,190 @@ type Config struct {
 	// Stderr for the process
 	Stderr io.Writer
 
+	// MaxOutputBytes caps how much of stdout/stderr is captured into
+	// Result.Stdout/Stderr when Stdout/Stderr above are nil, per stream.
+	// Zero uses a 1 MB default. Has no effect when Stdout/Stderr are set,
+	// since output then goes straight to the caller's writer instead of
+	// through the internal capture buffer.
+	MaxOutputBytes int64
+
 	// NetworkAccess enables/disables network access
 	NetworkAccess bool
 
 	// MaxProcesses limits the number of processes
 	MaxProcesses int
+
+	// CPUTimeLimit caps total CPU time (RLIMIT_CPU), rounded up to the
+	// nearest second since that's the rlimit's own granularity. Zero
+	// leaves it unset.
+	CPUTimeLimit time.Duration
+
+	// StackLimit caps the process's stack size in bytes (RLIMIT_STACK).
+	// -1 means unlimited; 0 (the default) inherits MemoryLimit.
+	StackLimit int64
+
+	// FileSizeLimit caps the size of any file the process writes, in
+	// bytes (RLIMIT_FSIZE). Zero leaves it unset.
+	FileSizeLimit int64
+
+	// CoreLimit caps core dump size in bytes (RLIMIT_CORE). Unlike the
+	// other limits here, zero is an active choice - it disables core
+	// dumps entirely, which is the default. -1 means unlimited.
+	CoreLimit int64
+
+	// RealTimeLimit enforces a wall-clock deadline in-kernel via
+	// SIGALRM, independently of Timeout: Timeout is the context-based
+	// outer bound Run itself observes, while RealTimeLimit keeps
+	// ticking even if the process survives past exec into a new image
+	// (e.g. a setuid binary) that a context cancellation can't reach.
+	// Zero disables it.
+	RealTimeLimit time.Duration
+
+	// Driver selects which registered backend (see Register,
+	// NewWithDriver) New should use. Empty selects "native", the
+	// built-in cgroup/job-object backend.
+	Driver string
+
+	// Events, if set, receives a stream of Event values describing the
+	// sandboxed process's lifecycle and resource usage as it runs.
+	// Callers own the channel's lifetime and should size its buffer (or
+	// drain it promptly) so a slow reader doesn't stall the sampler.
+	// Nil disables event delivery.
+	Events chan<- Event
+
+	// SampleInterval controls how often ResourceSample events are
+	// emitted while Events is set. Defaults to 500ms.
+	SampleInterval time.Duration
+
+	// SeccompProfile restricts the syscalls the child is allowed to make
+	// (Linux only). Nil disables seccomp filtering.
+	SeccompProfile *SeccompProfile
+
+	// Namespaces is a bitmask of the Linux namespaces to isolate the
+	// child into (Linux only). See the NS* constants. Zero means no
+	// namespace isolation.
+	Namespaces NamespaceFlags
+
+	// Hostname sets the hostname visible inside a new UTS namespace.
+	// Requires Namespaces&NSUTS.
+	Hostname string
+
+	// RootFS, if set, is the directory the child pivots into as its new
+	// root filesystem (Linux only). Requires Namespaces&NSMount.
+	RootFS string
+
+	// Mounts lists additional filesystems to mount inside RootFS before
+	// the target is exec'd.
+	Mounts []MountSpec
+
+	// UIDMap/GIDMap configure the child's user namespace ID mappings.
+	// When Namespaces&NSUser is set but these are empty, a single
+	// mapping from the current uid/gid to root (0) inside the
+	// namespace is used, matching rootless container runtimes.
+	UIDMap []IDMap
+	GIDMap []IDMap
+}
+
+// NamespaceFlags is a bitmask of Linux namespaces to isolate a sandboxed
+// process into.
+type NamespaceFlags uint32
+
+const (
+	NSPid   NamespaceFlags = 1 << iota // new PID namespace
+	NSNet                              // new network namespace
+	NSMount                            // new mount namespace
+	NSUTS                              // new UTS (hostname) namespace
+	NSIPC                              // new IPC namespace
+	NSUser                             // new user namespace
+)
+
+// MountSpec describes a filesystem to mount inside the sandbox's RootFS.
+type MountSpec struct {
+	// Source is the host path (or special source, e.g. "proc") to mount.
+	Source string
+
+	// Target is the path inside RootFS to mount onto.
+	Target string
+
+	// FSType is the filesystem type, e.g. "proc", "tmpfs", or "" for a
+	// bind mount.
+	FSType string
+
+	// ReadOnly remounts the mount read-only after binding it.
+	ReadOnly bool
+}
+
+// SeccompAction is the action a seccomp filter takes for a syscall.
+// Filtering itself is Linux-only, but the type lives here (rather than
+// in seccomp_linux.go) so Config.SeccompProfile's type is defined on
+// every platform the module builds for, matching NamespaceFlags/
+// MountSpec/IDMap.
+type SeccompAction int
+
+const (
+	// SeccompActionAllow permits the syscall to run.
+	SeccompActionAllow SeccompAction = iota
+	// SeccompActionKill kills the process immediately (SECCOMP_RET_KILL_PROCESS).
+	SeccompActionKill
+)
+
+// SeccompProfile describes a seccomp-bpf filter to install on the child
+// before it execs the target command (Linux only; see
+// Config.SeccompProfile). Syscalls not named in Allow/Deny fall back to
+// DefaultAction. The native Linux driver only resolves names listed in
+// its amd64 syscall table; other names in Allow/Deny are ignored.
+type SeccompProfile struct {
+	// DefaultAction is applied to syscalls not listed in Allow or Deny.
+	DefaultAction SeccompAction
+
+	// Allow lists syscalls that are always permitted, regardless of
+	// DefaultAction.
+	Allow []string
+
+	// Deny lists syscalls that are always killed, regardless of
+	// DefaultAction.
+	Deny []string
+}
+
+// DefaultSeccompProfile returns a "safe" profile modeled on Docker's
+// default seccomp profile: everything is allowed except a short list of
+// syscalls that expose kernel attack surface with little legitimate use
+// in sandboxed workloads.
+func DefaultSeccompProfile() *SeccompProfile {
+	return &SeccompProfile{
+		DefaultAction: SeccompActionAllow,
+		Deny: []string{
+			"mount",
+			"umount2",
+			"pivot_root",
+			"kexec_load",
+			"kexec_file_load",
+			"ptrace",
+			"reboot",
+			"swapon",
+			"swapoff",
+			"init_module",
+			"finit_module",
+			"delete_module",
+			"acct",
+			"clock_adjtime",
+			"clock_settime",
+			"settimeofday",
+			"stime",
+		},
+	}
+}
+
+// IDMap is a single entry of a /proc/[pid]/{uid,gid}_map mapping.
+type IDMap struct {
+	// ContainerID is the first ID inside the namespace.
+	ContainerID int
+
+	// HostID is the first ID outside the namespace it maps to.
+	HostID int
+
+	// Size is the number of consecutive IDs mapped.
+	Size int
 }
 
 // Result contains the execution result
@@ -56,18 +245,227 @@ type Result struct {
 	// Whether the process was killed due to timeout
 	TimedOut bool
 
+	// GracefullyTerminated is true if InterruptSignal alone made the
+	// process exit after a timeout, without needing to escalate to
+	// SIGKILL after KillDelay. Only meaningful when TimedOut is true.
+	GracefullyTerminated bool
+
 	// Whether the process exceeded memory limit
 	MemoryExceeded bool
 
+	// CPUTimeExceeded is true if the process was killed for exceeding
+	// CPUTimeLimit (SIGXCPU).
+	CPUTimeExceeded bool
+
+	// FileSizeExceeded is true if the process was killed for exceeding
+	// FileSizeLimit (SIGXFSZ).
+	FileSizeExceeded bool
+
+	// RealTimeExceeded is true if the process was killed for exceeding
+	// RealTimeLimit (SIGALRM).
+	RealTimeExceeded bool
+
+	// UserCPUTime/SystemCPUTime are the rusage-reported time spent in
+	// user and kernel mode, respectively.
+	UserCPUTime   time.Duration
+	SystemCPUTime time.Duration
+
+	// MaxRSSBytes is the peak resident set size reported by getrusage,
+	// normalized to bytes across platforms.
+	MaxRSSBytes int64
+
+	// MinorPageFaults/MajorPageFaults are page faults that didn't and
+	// did require I/O to service, respectively.
+	MinorPageFaults int64
+	MajorPageFaults int64
+
+	// VoluntaryCtxSwitches/InvoluntaryCtxSwitches count context switches
+	// the process made by blocking versus ones the scheduler forced.
+	VoluntaryCtxSwitches   int64
+	InvoluntaryCtxSwitches int64
+
+	// IOBlocksIn/IOBlocksOut are the number of block I/O operations the
+	// process performed, as reported by getrusage.
+	IOBlocksIn  int64
+	IOBlocksOut int64
+
+	// Stdout/Stderr hold the captured tail of the process's output, when
+	// Config.Stdout/Stderr were nil. Nil when the caller supplied its own
+	// writer instead.
+	Stdout []byte
+	Stderr []byte
+
+	// StdoutTruncated/StderrTruncated are true if the process wrote more
+	// than Config.MaxOutputBytes to the corresponding stream.
+	StdoutTruncated bool
+	StderrTruncated bool
+
+	// StdoutBytesWritten/StderrBytesWritten are the total bytes written
+	// to each stream, including any that were truncated away.
+	StdoutBytesWritten int64
+	StderrBytesWritten int64
+
+	// Whether the process was killed by the seccomp filter
+	KilledBySeccomp bool
+
+	// SeccompViolation describes the syscall that triggered the kill,
+	// when the kernel made that information available. Nil if the
+	// process wasn't killed by seccomp or the syscall couldn't be
+	// determined.
+	SeccompViolation *SeccompViolation
+
+	// PeakRSS is the highest resident set size observed during
+	// execution, in bytes.
+	PeakRSS int64
+
+	// CPUTime is total CPU time consumed by the process.
+	CPUTime time.Duration
+
+	// IOStats reports bytes read/written by the process, when the
+	// sandbox's driver can observe it.
+	IOStats IOStats
+
 	// Error if any
 	Error error
 }
 
+// IOStats reports I/O byte counters for a sandboxed process.
+type IOStats struct {
+	ReadBytes  int64
+	WriteBytes int64
+}
+
+// EventType identifies what kind of Event occurred.
+type EventType int
+
+const (
+	// EventProcessStarted fires once the sandboxed process has started.
+	EventProcessStarted EventType = iota
+
+	// EventResourceSample fires periodically (see Config.SampleInterval)
+	// with a point-in-time resource usage snapshot.
+	EventResourceSample
+
+	// EventOOMKill fires when the kernel OOM-killed a process in the
+	// sandbox.
+	EventOOMKill
+
+	// EventCgroupThresholdExceeded fires when usage crosses a
+	// configured limit without yet having been killed for it (e.g.
+	// memory.current passing 90% of MemoryLimit).
+	EventCgroupThresholdExceeded
+
+	// EventChildForked fires when the sandboxed process forks a child.
+	EventChildForked
+
+	// EventProcessExited fires once the sandboxed process has exited.
+	EventProcessExited
+)
+
+// Event is a single occurrence in a sandboxed process's lifecycle,
+// delivered on Config.Events.
+type Event struct {
+	// Type identifies which kind of event this is; the fields that are
+	// meaningful depend on it (see the EventType constants).
+	Type EventType
+
+	// PID is the process the event concerns.
+	PID int
+
+	// PPID is the parent process ID, set for EventChildForked.
+	PPID int
+
+	// Sample holds the usage snapshot for EventResourceSample.
+	Sample *ResourceSample
+}
+
+// ResourceSample is a point-in-time resource usage snapshot delivered in
+// an EventResourceSample event.
+type ResourceSample struct {
+	// CPUPct is CPU usage as a percentage of one core since the
+	// previous sample.
+	CPUPct float64
+
+	// RSS is current resident set size in bytes.
+	RSS int64
+
+	// IOReadBytes/IOWriteBytes are cumulative I/O byte counters.
+	IOReadBytes  int64
+	IOWriteBytes int64
+
+	// NumPIDs is the number of processes currently in the sandbox.
+	NumPIDs int
+}
+
+// SeccompViolation identifies the syscall that caused a seccomp filter to
+// kill the sandboxed process.
+type SeccompViolation struct {
+	// Nr is the syscall number as seen by the kernel.
+	Nr int
+
+	// Name is the syscall name, if it could be resolved.
+	Name string
+}
+
 // Sandbox defines the interface for process sandboxing
 type Sandbox interface {
 	// Run executes a command in the sandbox
 	Run(ctx context.Context, command string, args ...string) (*Result, error)
 
+	// Start launches command in the sandbox without waiting for it to
+	// exit, returning a Process handle for supervising it. Unlike Run,
+	// the sandbox's resource limits (cgroup / job object) stay alive
+	// across the returned Process's lifetime so callers can join
+	// additional commands into it with Process.Exec.
+	Start(ctx context.Context, command string, args ...string) (Process, error)
+
 	// Cleanup releases any resources used by the sandbox
 	Cleanup() error
 }
+
+// Process is a handle to a command started with Sandbox.Start. Unlike
+// the Result returned by Run, a Process stays alive after the call
+// returns so callers can signal it, wait on it, join additional
+// commands into the same job, and poll its resource usage - the same
+// shape as containerd's container API and Nomad's Executor.
+type Process interface {
+	// Pid returns the OS process ID the handle was started with.
+	Pid() int
+
+	// Signal delivers sig to the process.
+	Signal(sig os.Signal) error
+
+	// Wait blocks until the process exits and returns its Result.
+	Wait() (*Result, error)
+
+	// Exec starts an additional command inside the same sandbox job
+	// (cgroup on Linux, Job Object on Windows), sharing its resource
+	// limits and lifetime.
+	Exec(ctx context.Context, command string, args ...string) (Process, error)
+
+	// Stats samples current resource usage across every process in the
+	// job.
+	Stats() (*Stats, error)
+
+	// Pids lists the OS process IDs currently running in the job.
+	Pids() ([]int, error)
+}
+
+// Stats reports point-in-time resource usage for a sandboxed job,
+// aggregated across every process it contains.
+type Stats struct {
+	// CPUTime is total CPU time consumed by the job.
+	CPUTime time.Duration
+
+	// MemoryUsageBytes is current memory usage of the job.
+	MemoryUsageBytes int64
+
+	// IOReadBytes is total bytes read by the job.
+	IOReadBytes int64
+
+	// IOWriteBytes is total bytes written by the job.
+	IOWriteBytes int64
+
+	// NumPIDs is the number of processes currently running in the job.
+	NumPIDs int
+}
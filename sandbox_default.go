@@ -4,6 +4,7 @@ package sandbox
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -35,6 +36,12 @@ func NewDefaultSandbox(config *Config) (*DefaultSandbox, error) {
 	if config.MaxProcesses == 0 {
 		config.MaxProcesses = 50
 	}
+	if config.InterruptSignal == 0 {
+		config.InterruptSignal = syscall.SIGTERM
+	}
+	if config.KillDelay == 0 {
+		config.KillDelay = 2 * time.Second
+	}
 
 	sandbox := &DefaultSandbox{
 		config: config,
@@ -43,18 +50,47 @@ func NewDefaultSandbox(config *Config) (*DefaultSandbox, error) {
 	return sandbox, nil
 }
 
-// Run executes a command in the sandbox
-func (s *DefaultSandbox) Run(ctx context.Context, command string, args ...string) (*Result, error) {
-	result := &Result{}
-	startTime := time.Now()
-
-	// Create command context with timeout
-	cmdCtx, cancel := context.WithTimeout(ctx, s.config.Timeout)
-	defer cancel()
+// buildCommand assembles the *exec.Cmd for the sandboxed process,
+// including working directory, environment, and I/O.
+//
+// Rlimits can't be applied to cmd after Start (by then it's too late -
+// see golang/go#6603), so the command is always re-exec'd through the
+// SandboxMain trampoline instead of run directly: argv becomes
+// [trampoline, command, args...] and the rlimits to apply are passed via
+// the rlimitTrampolineEnv environment variable. This only works if the
+// host program calls SandboxMain at the top of its main(); if it
+// doesn't, the re-exec'd binary just runs the host program's own main()
+// again with the target command as its arguments, and the target is
+// never exec'd.
+func (s *DefaultSandbox) buildCommand(ctx context.Context, command string, args ...string) (*exec.Cmd, *outputBuffer, *outputBuffer, error) {
+	stack := s.config.StackLimit
+	if stack == 0 {
+		stack = s.config.MemoryLimit
+	}
+
+	setup := rlimitSetup{
+		AS:       s.config.MemoryLimit,
+		Data:     s.config.MemoryLimit,
+		Stack:    stack,
+		NProc:    int64(s.config.MaxProcesses),
+		FSize:    s.config.FileSizeLimit,
+		CPU:      int64((s.config.CPUTimeLimit + time.Second - 1) / time.Second),
+		Core:     s.config.CoreLimit,
+		RealTime: int64(s.config.RealTimeLimit),
+	}
+
+	target, err := trampolineTarget()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("resolving rlimit trampoline target: %w", err)
+	}
+	encoded, err := json.Marshal(setup)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("encoding rlimit setup: %w", err)
+	}
 
-	cmd := exec.CommandContext(cmdCtx, command, args...)
+	cmd := exec.CommandContext(ctx, target, append([]string{command}, args...)...)
+	cmd.Env = append(s.environ(), rlimitTrampolineEnv+"="+string(encoded))
 
-	// Setup resource limits using setrlimit
 	cmd.SysProcAttr = &syscall.SysProcAttr{
 		Setpgid: true,
 	}
@@ -64,77 +100,93 @@ func (s *DefaultSandbox) Run(ctx context.Context, command string, args ...string
 		cmd.Dir = s.config.WorkingDir
 	}
 
-	// Set environment variables
-	if len(s.config.Env) > 0 {
-		cmd.Env = s.config.Env
-	} else {
-		cmd.Env = os.Environ()
-	}
-
 	// Setup I/O
 	if s.config.Stdin != nil {
 		cmd.Stdin = s.config.Stdin
 	}
+	var stdoutBuf, stderrBuf *outputBuffer
 	if s.config.Stdout != nil {
 		cmd.Stdout = s.config.Stdout
+	} else {
+		stdoutBuf = newOutputBuffer(s.config.MaxOutputBytes)
+		cmd.Stdout = stdoutBuf
 	}
 	if s.config.Stderr != nil {
 		cmd.Stderr = s.config.Stderr
+	} else {
+		stderrBuf = newOutputBuffer(s.config.MaxOutputBytes)
+		cmd.Stderr = stderrBuf
 	}
 
-	// Start the process
-	err := cmd.Start()
-	if err != nil {
-		result.Error = fmt.Errorf("failed to start process: %w", err)
-		return result, err
+	return cmd, stdoutBuf, stderrBuf, nil
+}
+
+// environ returns the environment variables to run the sandboxed command
+// with, before any trampoline marker variable is appended.
+func (s *DefaultSandbox) environ() []string {
+	if len(s.config.Env) > 0 {
+		return append([]string{}, s.config.Env...)
 	}
+	return os.Environ()
+}
 
-	// Set resource limits on the process group
-	// Note: This is done after start as we need the PID
-	if s.config.MemoryLimit > 0 {
-		// Set memory limit (data segment)
-		rlimit := syscall.Rlimit{
-			Cur: uint64(s.config.MemoryLimit),
-			Max: uint64(s.config.MemoryLimit),
-		}
-		// Note: Setting limits on already running process has limited effect
-		// This is a best-effort approach
-		syscall.Setrlimit(syscall.RLIMIT_DATA, &rlimit)
-		syscall.Setrlimit(syscall.RLIMIT_AS, &rlimit)
+// Run executes a command in the sandbox
+func (s *DefaultSandbox) Run(ctx context.Context, command string, args ...string) (*Result, error) {
+	result := &Result{}
+	startTime := time.Now()
+
+	// Create command context with timeout. It's only used to decide when
+	// to send InterruptSignal below, not passed to exec.CommandContext,
+	// since that would SIGKILL the process itself as soon as it expired.
+	cmdCtx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	cmd, stdoutBuf, stderrBuf, err := s.buildCommand(ctx, command, args...)
+	if err != nil {
+		result.Error = err
+		return result, err
 	}
 
-	if s.config.MaxProcesses > 0 {
-		// Set process limit
-		rlimit := syscall.Rlimit{
-			Cur: uint64(s.config.MaxProcesses),
-			Max: uint64(s.config.MaxProcesses),
-		}
-		syscall.Setrlimit(syscall.RLIMIT_NPROC, &rlimit)
+	// Start the process
+	err = cmd.Start()
+	if err != nil {
+		result.Error = fmt.Errorf("failed to start process: %w", err)
+		return result, err
 	}
 
 	// Wait for completion
-	err = cmd.Wait()
+	var forced bool
+	err, forced = waitOrStop(cmdCtx, cmd, s.config.InterruptSignal, s.config.KillDelay)
 	result.ExecutionTime = time.Since(startTime)
+	populateOutput(result, stdoutBuf, stderrBuf)
 
 	// Check if timeout occurred
 	if cmdCtx.Err() == context.DeadlineExceeded {
 		result.TimedOut = true
 		result.Error = fmt.Errorf("execution timeout exceeded")
-		// Kill the process group
-		syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		result.GracefullyTerminated = !forced
 	}
 
+	populateRusage(result, cmd.ProcessState)
+
 	// Get exit code
 	if cmd.ProcessState != nil {
 		result.ExitCode = cmd.ProcessState.ExitCode()
 		
-		// Check if process was killed by signal (possibly OOM)
-		if ws, ok := cmd.ProcessState.Sys().(syscall.WaitStatus); ok {
-			if ws.Signaled() && ws.Signal() == syscall.SIGKILL {
-				// Could be OOM or timeout
+		// Check if process was killed by signal (possibly OOM, or one of
+		// the rlimit overage signals SandboxMain's applied limits raise)
+		if ws, ok := cmd.ProcessState.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+			switch ws.Signal() {
+			case syscall.SIGKILL:
 				if !result.TimedOut {
 					result.MemoryExceeded = true
 				}
+			case syscall.SIGXCPU:
+				result.CPUTimeExceeded = true
+			case syscall.SIGXFSZ:
+				result.FileSizeExceeded = true
+			case syscall.SIGALRM:
+				result.RealTimeExceeded = true
 			}
 		}
 	}
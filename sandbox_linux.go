@@ -4,6 +4,7 @@ package sandbox
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -40,6 +41,12 @@ func NewLinuxSandbox(config *Config) (*LinuxSandbox, error) {
 	if config.MaxProcesses == 0 {
 		config.MaxProcesses = 50
 	}
+	if config.InterruptSignal == 0 {
+		config.InterruptSignal = syscall.SIGTERM
+	}
+	if config.KillDelay == 0 {
+		config.KillDelay = 2 * time.Second
+	}
 
 	// Check for cgroup v2
 	useCgroupV2 := checkCgroupV2()
@@ -100,25 +107,74 @@ func (s *LinuxSandbox) setupCgroup() error {
 	return nil
 }
 
-// Run executes a command in the sandbox
-func (s *LinuxSandbox) Run(ctx context.Context, command string, args ...string) (*Result, error) {
-	result := &Result{}
-	startTime := time.Now()
+// buildCommand assembles the *exec.Cmd for the sandboxed process,
+// including working directory, environment, I/O and the re-exec
+// trampoline (see seccomp_linux.go's init) that applies rlimits and,
+// when Config.SeccompProfile or Config.RootFS is set, seccomp/rootfs
+// setup to the child before the target is exec'd. The trampoline runs
+// unconditionally, the same way DefaultSandbox's does, since
+// Config.CoreLimit's zero value (disable core dumps) is an active
+// choice that always needs applying, not an absence of one.
+func (s *LinuxSandbox) buildCommand(ctx context.Context, command string, args ...string) (*exec.Cmd, *outputBuffer, *outputBuffer, error) {
+	rootfsSetup, err := s.encodeRootfsSetup()
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
-	// Setup cgroup
-	s.setupCgroup()
+	self, err := os.Executable()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("resolving self path for sandbox trampoline: %w", err)
+	}
 
-	// Create command context with timeout
-	cmdCtx, cancel := context.WithTimeout(ctx, s.config.Timeout)
-	defer cancel()
+	// Resolve command against the parent's real PATH before handing off
+	// to the trampoline: the trampoline's LookPath runs against
+	// cmd.Env/os.Environ() of the re-exec'd child, which is
+	// s.config.Env verbatim whenever it's set, often without PATH at
+	// all. An absolute path sidesteps the child's PATH entirely.
+	resolved, err := exec.LookPath(command)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("resolving command: %w", err)
+	}
+	cmd := exec.CommandContext(ctx, self, append([]string{resolved}, args...)...)
+
+	if rootfsSetup != "" {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", rootfsTrampolineEnv, rootfsSetup))
+	}
+
+	stack := s.config.StackLimit
+	if stack == 0 {
+		stack = s.config.MemoryLimit
+	}
+	rlimits := rlimitSetup{
+		AS:       s.config.MemoryLimit,
+		Data:     s.config.MemoryLimit,
+		Stack:    stack,
+		NProc:    int64(s.config.MaxProcesses),
+		FSize:    s.config.FileSizeLimit,
+		CPU:      int64((s.config.CPUTimeLimit + time.Second - 1) / time.Second),
+		Core:     s.config.CoreLimit,
+		RealTime: int64(s.config.RealTimeLimit),
+	}
+	encodedRlimits, err := json.Marshal(rlimits)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("encoding rlimit setup: %w", err)
+	}
+	cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", rlimitTrampolineEnv, encodedRlimits))
 
-	cmd := exec.CommandContext(cmdCtx, command, args...)
+	if s.config.SeccompProfile != nil {
+		encoded, err := json.Marshal(s.config.SeccompProfile)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("encoding seccomp profile: %w", err)
+		}
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", seccompTrampolineEnv, encoded))
+	}
 
 	// Try to setup namespaces if possible (requires root or user namespaces)
 	// Don't fail if we can't set up namespaces - continue without them
 	cmd.SysProcAttr = &syscall.SysProcAttr{
 		Setpgid: true, // Create new process group for easier cleanup
 	}
+	s.applyNamespaces(cmd)
 
 	// Set working directory
 	if s.config.WorkingDir != "" {
@@ -127,24 +183,54 @@ func (s *LinuxSandbox) Run(ctx context.Context, command string, args ...string)
 
 	// Set environment variables
 	if len(s.config.Env) > 0 {
-		cmd.Env = s.config.Env
+		cmd.Env = append(cmd.Env, s.config.Env...)
 	} else {
-		cmd.Env = os.Environ()
+		cmd.Env = append(cmd.Env, os.Environ()...)
 	}
 
 	// Setup I/O
 	if s.config.Stdin != nil {
 		cmd.Stdin = s.config.Stdin
 	}
+	var stdoutBuf, stderrBuf *outputBuffer
 	if s.config.Stdout != nil {
 		cmd.Stdout = s.config.Stdout
+	} else {
+		stdoutBuf = newOutputBuffer(s.config.MaxOutputBytes)
+		cmd.Stdout = stdoutBuf
 	}
 	if s.config.Stderr != nil {
 		cmd.Stderr = s.config.Stderr
+	} else {
+		stderrBuf = newOutputBuffer(s.config.MaxOutputBytes)
+		cmd.Stderr = stderrBuf
+	}
+
+	return cmd, stdoutBuf, stderrBuf, nil
+}
+
+// Run executes a command in the sandbox
+func (s *LinuxSandbox) Run(ctx context.Context, command string, args ...string) (*Result, error) {
+	result := &Result{}
+	startTime := time.Now()
+
+	// Setup cgroup
+	s.setupCgroup()
+
+	// Create command context with timeout. It's only used to decide when
+	// to send InterruptSignal below, not passed to exec.CommandContext,
+	// since that would SIGKILL the process itself as soon as it expired.
+	cmdCtx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	cmd, stdoutBuf, stderrBuf, err := s.buildCommand(ctx, command, args...)
+	if err != nil {
+		result.Error = err
+		return result, err
 	}
 
 	// Start the process
-	err := cmd.Start()
+	err = cmd.Start()
 	if err != nil {
 		result.Error = fmt.Errorf("failed to start process: %w", err)
 		return result, err
@@ -156,25 +242,49 @@ func (s *LinuxSandbox) Run(ctx context.Context, command string, args ...string)
 		os.WriteFile(procsPath, []byte(strconv.Itoa(cmd.Process.Pid)), 0644)
 	}
 
+	sampler := newEventSampler(s, cmd.Process.Pid)
+
 	// Wait for completion
-	err = cmd.Wait()
+	var forced bool
+	err, forced = waitOrStop(cmdCtx, cmd, s.config.InterruptSignal, s.config.KillDelay)
 	result.ExecutionTime = time.Since(startTime)
+	populateOutput(result, stdoutBuf, stderrBuf)
+
+	if sampler != nil {
+		sampler.Stop(cmd.Process.Pid)
+		sampler.populateResult(result)
+	}
 
 	// Check if timeout occurred
 	if cmdCtx.Err() == context.DeadlineExceeded {
 		result.TimedOut = true
 		result.Error = fmt.Errorf("execution timeout exceeded")
-		// Kill the process group if it's still running
-		if cmd.Process != nil {
-			syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
-		}
+		result.GracefullyTerminated = !forced
 	}
 
+	populateRusage(result, cmd.ProcessState)
+
 	// Get exit code
 	if cmd.ProcessState != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
 			if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
 				result.ExitCode = status.ExitStatus()
+				if status.Signaled() {
+					switch status.Signal() {
+					case syscall.SIGSYS:
+						// A seccomp filter configured to kill the
+						// process on a denied syscall terminates it
+						// with SIGSYS.
+						result.KilledBySeccomp = true
+						result.SeccompViolation = readSeccompViolation(cmd.Process.Pid)
+					case syscall.SIGXCPU:
+						result.CPUTimeExceeded = true
+					case syscall.SIGXFSZ:
+						result.FileSizeExceeded = true
+					case syscall.SIGALRM:
+						result.RealTimeExceeded = true
+					}
+				}
 			}
 		} else if err == nil {
 			result.ExitCode = 0
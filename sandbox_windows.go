@@ -18,12 +18,15 @@ var (
 	procCreateJobObjectW          = kernel32.NewProc("CreateJobObjectW")
 	procAssignProcessToJobObject  = kernel32.NewProc("AssignProcessToJobObject")
 	procSetInformationJobObject   = kernel32.NewProc("SetInformationJobObject")
+	procQueryInformationJobObject = kernel32.NewProc("QueryInformationJobObject")
 	procTerminateJobObject        = kernel32.NewProc("TerminateJobObject")
 	procCloseHandle               = kernel32.NewProc("CloseHandle")
 )
 
 const (
+	JobObjectBasicAccountingInformation      = 1
 	JobObjectBasicLimitInformation           = 2
+	JobObjectBasicProcessIdList              = 3
 	JobObjectExtendedLimitInformation        = 9
 	JOB_OBJECT_LIMIT_PROCESS_MEMORY          = 0x00000100
 	JOB_OBJECT_LIMIT_JOB_MEMORY              = 0x00000200
@@ -32,6 +35,28 @@ const (
 	JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE       = 0x00002000
 )
 
+// JOBOBJECT_BASIC_ACCOUNTING_INFORMATION mirrors the Win32 struct of the
+// same name, as returned by QueryInformationJobObject with
+// JobObjectBasicAccountingInformation.
+type JOBOBJECT_BASIC_ACCOUNTING_INFORMATION struct {
+	TotalUserTime             int64
+	TotalKernelTime           int64
+	ThisPeriodTotalUserTime   int64
+	ThisPeriodTotalKernelTime int64
+	TotalPageFaultCount       uint32
+	TotalProcesses            uint32
+	ActiveProcesses           uint32
+	TotalTerminatedProcesses  uint32
+}
+
+// jobObjectBasicProcessIDListHeader mirrors the fixed-size header of
+// JOBOBJECT_BASIC_PROCESS_ID_LIST; the variable-length ProcessIdList
+// array that follows it is read separately.
+type jobObjectBasicProcessIDListHeader struct {
+	NumberOfAssignedProcesses uint32
+	NumberOfProcessIdsInList  uint32
+}
+
 type JOBOBJECT_BASIC_LIMIT_INFORMATION struct {
 	PerProcessUserTimeLimit int64
 	PerJobUserTimeLimit     int64
@@ -137,22 +162,10 @@ func (s *WindowsSandbox) createJobObject() error {
 	return nil
 }
 
-// Run executes a command in the sandbox
-func (s *WindowsSandbox) Run(ctx context.Context, command string, args ...string) (*Result, error) {
-	result := &Result{}
-	startTime := time.Now()
-
-	// Create job object
-	if err := s.createJobObject(); err != nil {
-		result.Error = err
-		return result, err
-	}
-
-	// Create command context with timeout
-	cmdCtx, cancel := context.WithTimeout(ctx, s.config.Timeout)
-	defer cancel()
-
-	cmd := exec.CommandContext(cmdCtx, command, args...)
+// buildCommand assembles the *exec.Cmd for the sandboxed process,
+// including working directory, environment, and I/O.
+func (s *WindowsSandbox) buildCommand(ctx context.Context, command string, args ...string) (*exec.Cmd, *outputBuffer, *outputBuffer) {
+	cmd := exec.CommandContext(ctx, command, args...)
 
 	// Set working directory
 	if s.config.WorkingDir != "" {
@@ -170,11 +183,18 @@ func (s *WindowsSandbox) Run(ctx context.Context, command string, args ...string
 	if s.config.Stdin != nil {
 		cmd.Stdin = s.config.Stdin
 	}
+	var stdoutBuf, stderrBuf *outputBuffer
 	if s.config.Stdout != nil {
 		cmd.Stdout = s.config.Stdout
+	} else {
+		stdoutBuf = newOutputBuffer(s.config.MaxOutputBytes)
+		cmd.Stdout = stdoutBuf
 	}
 	if s.config.Stderr != nil {
 		cmd.Stderr = s.config.Stderr
+	} else {
+		stderrBuf = newOutputBuffer(s.config.MaxOutputBytes)
+		cmd.Stderr = stderrBuf
 	}
 
 	// Ensure CREATE_SUSPENDED and CREATE_BREAKAWAY_FROM_JOB flags
@@ -182,6 +202,26 @@ func (s *WindowsSandbox) Run(ctx context.Context, command string, args ...string
 		CreationFlags: syscall.CREATE_SUSPENDED | 0x01000000, // CREATE_BREAKAWAY_FROM_JOB
 	}
 
+	return cmd, stdoutBuf, stderrBuf
+}
+
+// Run executes a command in the sandbox
+func (s *WindowsSandbox) Run(ctx context.Context, command string, args ...string) (*Result, error) {
+	result := &Result{}
+	startTime := time.Now()
+
+	// Create job object
+	if err := s.createJobObject(); err != nil {
+		result.Error = err
+		return result, err
+	}
+
+	// Create command context with timeout
+	cmdCtx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	cmd, stdoutBuf, stderrBuf := s.buildCommand(cmdCtx, command, args...)
+
 	// Start the process
 	err := cmd.Start()
 	if err != nil {
@@ -207,6 +247,7 @@ func (s *WindowsSandbox) Run(ctx context.Context, command string, args ...string
 	// Wait for completion
 	err = cmd.Wait()
 	result.ExecutionTime = time.Since(startTime)
+	populateOutput(result, stdoutBuf, stderrBuf)
 
 	// Check if timeout occurred
 	if cmdCtx.Err() == context.DeadlineExceeded {
@@ -0,0 +1,16 @@
+package sandbox
+
+// sandboxHelperPath is the binary SandboxMain's trampoline re-execs into
+// helper mode. Empty means "re-exec the running binary itself", which
+// only works when that binary calls SandboxMain at the top of its own
+// main - see RegisterSandboxHelper for programs that would rather ship
+// a small dedicated helper binary instead.
+var sandboxHelperPath string
+
+// RegisterSandboxHelper points the rlimit trampoline (see SandboxMain)
+// at a separate helper binary instead of re-exec'ing the calling
+// program. Useful when the calling program is large, not a Go binary
+// that calls SandboxMain itself, or otherwise unsuitable to re-exec.
+func RegisterSandboxHelper(path string) {
+	sandboxHelperPath = path
+}
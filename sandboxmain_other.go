@@ -0,0 +1,10 @@
+// +build linux windows
+
+package sandbox
+
+// SandboxMain is a no-op on this platform: on Linux the pre-exec rlimit
+// trampoline runs from this package's init (see seccomp_linux.go), so no
+// explicit call is needed, and Windows enforces limits via Job Objects
+// (see WindowsSandbox). It exists here so callers that target multiple platforms
+// can call sandbox.SandboxMain() unconditionally at the top of main().
+func SandboxMain() {}
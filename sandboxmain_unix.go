@@ -0,0 +1,128 @@
+// +build darwin !linux,!windows
+
+package sandbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// SandboxMain must be called at the very top of a program's main()
+// function (before flag parsing, before anything else) for
+// DefaultSandbox's rlimits to actually reach the sandboxed child.
+//
+// Go has no way to run code between fork and exec the way C's
+// posix_spawn_file_actions or a pre-exec hook can, so calling
+// syscall.Setrlimit after cmd.Start() only ever limits the already-running
+// parent, never the child (see golang/go#6603). The workaround, used by
+// SwiftShader's test harness among others, is to re-exec the program
+// itself (or a dedicated helper registered with RegisterSandboxHelper)
+// with the rlimits to apply and the real command passed through; that
+// re-exec'd instance calls Setrlimit on *itself* - which is about to
+// become the target process - and then syscall.Exec's straight into it.
+//
+// If the calling process wasn't launched as that trampoline (the normal
+// case), SandboxMain detects that from the absence of its marker
+// environment variable and returns immediately, so it's safe to call
+// unconditionally. cmd.SysProcAttr.Setpgid is still required for group
+// kill on timeout - SandboxMain only fixes rlimits, not process
+// grouping.
+func SandboxMain() {
+	encoded := os.Getenv(rlimitTrampolineEnv)
+	if encoded == "" {
+		return
+	}
+	os.Unsetenv(rlimitTrampolineEnv)
+
+	var setup rlimitSetup
+	if err := json.Unmarshal([]byte(encoded), &setup); err != nil {
+		fmt.Fprintf(os.Stderr, "go-proc-sandbox: invalid rlimit setup: %v\n", err)
+		os.Exit(127)
+	}
+
+	applyRlimit(syscall.RLIMIT_AS, setup.AS)
+	applyRlimit(syscall.RLIMIT_DATA, setup.Data)
+	applyRlimit(syscall.RLIMIT_STACK, setup.Stack)
+	applyRlimit(rlimitNproc, setup.NProc)
+	applyRlimit(syscall.RLIMIT_FSIZE, setup.FSize)
+	applyRlimit(syscall.RLIMIT_CPU, setup.CPU)
+	applyCoreLimit(setup.Core)
+
+	if setup.RealTime > 0 {
+		if err := installRealTimeLimit(setup.RealTime); err != nil {
+			fmt.Fprintf(os.Stderr, "go-proc-sandbox: installing real-time limit: %v\n", err)
+			os.Exit(127)
+		}
+	}
+
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "go-proc-sandbox: missing target command for rlimit trampoline")
+		os.Exit(127)
+	}
+	target, err := exec.LookPath(os.Args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "go-proc-sandbox: %v\n", err)
+		os.Exit(127)
+	}
+	if err := syscall.Exec(target, os.Args[1:], os.Environ()); err != nil {
+		fmt.Fprintf(os.Stderr, "go-proc-sandbox: exec %s: %v\n", target, err)
+		os.Exit(127)
+	}
+}
+
+// itimerval mirrors the C struct itimerval passed to setitimer(2).
+type itimerval struct {
+	IntervalSec  int64
+	IntervalUsec int64
+	ValueSec     int64
+	ValueUsec    int64
+}
+
+const (
+	itimerReal = 0
+
+	// sysSetitimer is darwin/amd64's setitimer(2) syscall number. Other
+	// Unix targets this file also builds for (the "!linux,!windows" half
+	// of its build tag) aren't covered; RealTimeLimit is a no-op there.
+	sysSetitimer = 83
+
+	// rlimitNproc is RLIMIT_NPROC, which the stdlib syscall package
+	// doesn't define for darwin or the BSDs; all of them use 7.
+	rlimitNproc = 7
+)
+
+// installRealTimeLimit arms an ITIMER_REAL alarm that delivers SIGALRM
+// after d. Interval timers survive execve (only a process's signal
+// handlers reset to their defaults), and SIGALRM's default disposition
+// is to terminate the process, so this keeps ticking straight through
+// the syscall.Exec below into the real target with no further code
+// needed in the new process image.
+func installRealTimeLimit(d int64) error {
+	if runtime.GOOS != "darwin" {
+		return nil
+	}
+	it := itimerval{
+		ValueSec:  d / int64(time.Second),
+		ValueUsec: (d % int64(time.Second)) / int64(time.Microsecond),
+	}
+	if _, _, errno := syscall.Syscall(sysSetitimer, itimerReal, uintptr(unsafe.Pointer(&it)), 0); errno != 0 {
+		return fmt.Errorf("setitimer(ITIMER_REAL): %w", errno)
+	}
+	return nil
+}
+
+// trampolineTarget returns the binary buildCommand should re-exec into
+// for the rlimit trampoline: the registered helper, or the running
+// program itself.
+func trampolineTarget() (string, error) {
+	if sandboxHelperPath != "" {
+		return sandboxHelperPath, nil
+	}
+	return os.Executable()
+}
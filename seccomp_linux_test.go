@@ -0,0 +1,61 @@
+// +build linux
+
+package sandbox
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSeccompDenyKillsProcess(t *testing.T) {
+	config := &Config{
+		Timeout:     5 * time.Second,
+		MemoryLimit: 100 * 1024 * 1024,
+		SeccompProfile: &SeccompProfile{
+			DefaultAction: SeccompActionAllow,
+			Deny:          []string{"mkdir", "mkdirat"},
+		},
+	}
+	sb, err := New(config)
+	if err != nil {
+		t.Fatalf("Failed to create sandbox: %v", err)
+	}
+	defer sb.Cleanup()
+
+	result, err := sb.Run(context.Background(), "mkdir", "/tmp/go-proc-sandbox-seccomp-test-dir")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if !result.KilledBySeccomp {
+		t.Errorf("Expected the process to be killed by seccomp, got Result %+v", result)
+	}
+}
+
+func TestSeccompAllowsUndeniedSyscalls(t *testing.T) {
+	config := &Config{
+		Timeout:     5 * time.Second,
+		MemoryLimit: 100 * 1024 * 1024,
+		SeccompProfile: &SeccompProfile{
+			DefaultAction: SeccompActionAllow,
+			Deny:          []string{"mount", "umount2"},
+		},
+	}
+	sb, err := New(config)
+	if err != nil {
+		t.Fatalf("Failed to create sandbox: %v", err)
+	}
+	defer sb.Cleanup()
+
+	result, err := sb.Run(context.Background(), "sh", "-c", "exit 0")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.KilledBySeccomp {
+		t.Errorf("Expected a syscall not in Deny to run unaffected, got Result %+v", result)
+	}
+	if result.ExitCode != 0 {
+		t.Errorf("Expected exit code 0, got %d", result.ExitCode)
+	}
+}